@@ -75,15 +75,10 @@ func (h happyHandler) Content(results iter.Seq2[string, any]) (any, error) {
 }
 
 func (h happyHandler) Tag(results iter.Seq2[string, any]) (any, error) {
-	_, value := funki.FirstOf(results, "Value", "Else", "Include", "Section", "Partial", "Override", "Otherwise")
+	_, value := funki.FirstOf(results, "Value", "Else", "Include", "Section", "Partial", "Override")
 	return value, nil
 }
 
-func (h happyHandler) Otherwise(results iter.Seq2[string, any]) (any, error) {
-	_, value := funki.FirstOf(results, "Text")
-	return Plaintext(value.(string)), nil
-}
-
 func (h happyHandler) Value(results iter.Seq2[string, any]) (any, error) {
 	_, value := funki.FirstOf(results, "Key")
 	return Reference(value.(Key)), nil
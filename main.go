@@ -18,13 +18,19 @@ func panicUnless[T any](t T, err error) T {
 
 func main() {
 	var configPath string
+	var imperative bool
 	flag.StringVar(&configPath, "c", "config.json", "path to the json config file")
+	flag.BoolVar(&imperative, "i", false, "generate an imperative parser instead of the grammar")
 	flag.Parse()
 	grammarPath := flag.Arg(0)
 	config := panicUnless(os.ReadFile(configPath))
 	grammar := panicUnless(os.ReadFile(grammarPath))
 	opts := panicUnless(sample.ParseJson(string(config))).(map[string]any)
 	rules := panicUnless(parser.Bootstrap(string(grammar)))
+	if imperative {
+		fmt.Print(string(panicUnless(parser.Generate(rules, opts))))
+		return
+	}
 	result := panicUnless(sample.RenderPeg(rules, opts))
 	fmt.Print(result)
 }
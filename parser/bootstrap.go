@@ -2,7 +2,9 @@ package parser
 
 import (
 	"iter"
+	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/fuwjax/gopase/funki"
@@ -11,32 +13,112 @@ import (
 /*
 The Peg-grammar parser.
 */
-var Bootstrap = BootstrapParser[*Grammar]("Grammar", PegGrammar(), PegHandler)
+var Bootstrap = BootstrapParser[*Grammar]("Grammar", PegImperative(), PegHandler)
 
-var BootstrapFrom = BootstrapParserFrom(PegGrammar(), PegHandler)
+var BootstrapFrom = BootstrapParserFrom(PegImperative(), PegHandler)
 
 var PegHandler = WrapHandler(pegHandler{})
 
-type pegHandler struct{}
+/*
+pegHandler builds grammars from the PEG syntax. Import paths are relative to
+dir, the directory of the grammar being loaded, or to the working directory
+if it is empty. Loading holds the files being loaded, to catch import cycles.
+*/
+type pegHandler struct {
+	dir     string
+	loading []string
+}
 
 func (p pegHandler) Grammar(result iter.Seq2[string, any]) (any, error) {
-	rules := slices.Collect(funki.Cast[*Rule](funki.FilterNonNil(funki.Values(funki.FilterKeys(result, "Line")))))
+	lines := slices.Collect(funki.FilterNonNil(funki.Values(funki.FilterKeys(result, "Line"))))
 	grammar := NewGrammar()
-	for _, rule := range rules {
-		grammar.Add(rule)
+	for _, line := range lines {
+		if imp, ok := line.(*grammarImport); ok {
+			if err := grammar.Import(imp.namespace, imp.grammar); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, line := range lines {
+		if rule, ok := line.(*Rule); ok {
+			grammar.Add(rule)
+		}
+	}
+	for _, line := range lines {
+		switch directive := line.(type) {
+		case *lexicalDirective:
+			grammar.mark(directive.kind, directive.names)
+		case *skipDirective:
+			grammar.Skip(directive.expr)
+		}
 	}
 	return grammar, nil
 }
 
 func (p pegHandler) Line(result iter.Seq2[string, any]) (any, error) {
-	_, rule := funki.FirstOf(result, "Rule")
-	return rule, nil
+	_, line := funki.FirstOf(result, "Import", "Tokens", "Ignores", "Lexicals", "Skip", "Rule")
+	return line, nil
+}
+
+func (p pegHandler) Import(result iter.Seq2[string, any]) (any, error) {
+	_, name := funki.FirstOf(result, "Name")
+	_, path := funki.FirstOf(result, "Path")
+	_, alias := funki.FirstOf(result, "Alias")
+	nameStr, _ := name.(string)
+	pathStr, _ := path.(string)
+	aliasStr, _ := alias.(string)
+	return resolveImport(nameStr, p.resolvePath(pathStr), aliasStr, p.loading)
+}
+
+func (p pegHandler) resolvePath(path string) string {
+	if path == "" || p.dir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(p.dir, path)
+}
+
+func (p pegHandler) Path(result iter.Seq2[string, any]) (any, error) {
+	_, value := funki.FirstOf(result, "SingleLit", "DoubleLit")
+	return value, nil
+}
+
+func (p pegHandler) Tokens(result iter.Seq2[string, any]) (any, error) {
+	return &lexicalDirective{token, funki.ListOf[string](result, "Name")}, nil
+}
+
+func (p pegHandler) Ignores(result iter.Seq2[string, any]) (any, error) {
+	return &lexicalDirective{ignored, funki.ListOf[string](result, "Name")}, nil
+}
+
+func (p pegHandler) Lexicals(result iter.Seq2[string, any]) (any, error) {
+	return &lexicalDirective{exact, funki.ListOf[string](result, "Name")}, nil
+}
+
+func (p pegHandler) Skip(result iter.Seq2[string, any]) (any, error) {
+	_, expr := funki.FirstOf(result, "Expr")
+	return &skipDirective{expr.(Expr)}, nil
 }
 
 func (p pegHandler) Rule(result iter.Seq2[string, any]) (any, error) {
 	_, name := funki.FirstOf(result, "Name")
 	_, expr := funki.FirstOf(result, "Expr")
-	return NewRule(name.(string), expr.(Expr)), nil
+	_, description := funki.FirstOf(result, "Description")
+	_, params := funki.FirstOf(result, "Params")
+	names, _ := params.([]string)
+	rule := NewRule(name.(string), expr.(Expr), names...)
+	if description != nil {
+		rule.Describe(description.(string))
+	}
+	return rule, nil
+}
+
+func (p pegHandler) Params(result iter.Seq2[string, any]) (any, error) {
+	return funki.ListOf[string](result, "Name"), nil
+}
+
+func (p pegHandler) Description(result iter.Seq2[string, any]) (any, error) {
+	_, value := funki.FirstOf(result, "SingleLit", "DoubleLit")
+	return value, nil
 }
 
 func (p pegHandler) Expr(result iter.Seq2[string, any]) (any, error) {
@@ -50,10 +132,16 @@ func (p pegHandler) Seq(result iter.Seq2[string, any]) (any, error) {
 }
 
 func (p pegHandler) Prefix(result iter.Seq2[string, any]) (any, error) {
-	_, value := funki.FirstOf(result, "AndExpr", "NotExpr", "Suffix")
+	_, value := funki.FirstOf(result, "LabelExpr", "AndExpr", "NotExpr", "Suffix")
 	return value, nil
 }
 
+func (p pegHandler) LabelExpr(result iter.Seq2[string, any]) (any, error) {
+	_, label := funki.FirstOf(result, "Label")
+	_, expr := funki.FirstOf(result, "Prefix")
+	return Label(label.(string), expr.(Expr)), nil
+}
+
 func (p pegHandler) AndExpr(result iter.Seq2[string, any]) (any, error) {
 	_, expr := funki.FirstOf(result, "Suffix")
 	return See(expr.(Expr)), nil
@@ -65,7 +153,7 @@ func (p pegHandler) NotExpr(result iter.Seq2[string, any]) (any, error) {
 }
 
 func (p pegHandler) Suffix(result iter.Seq2[string, any]) (any, error) {
-	_, value := funki.FirstOf(result, "OptExpr", "RepExpr", "ReqExpr", "Primary")
+	_, value := funki.FirstOf(result, "OptExpr", "RepExpr", "ReqExpr", "BoundExpr", "RecoverExpr", "Primary")
 	return value, nil
 }
 
@@ -84,8 +172,51 @@ func (p pegHandler) ReqExpr(result iter.Seq2[string, any]) (any, error) {
 	return Req(expr.(Expr)), nil
 }
 
+func (p pegHandler) BoundExpr(result iter.Seq2[string, any]) (any, error) {
+	_, expr := funki.FirstOf(result, "Primary")
+	_, min := funki.FirstOf(result, "Min")
+	_, max := funki.FirstOf(result, "Upto")
+	if max == nil {
+		max = min
+	}
+	bound := &Bounded{expr.(Expr), min.(int), max.(int)}
+	if err := bound.check(); err != nil {
+		return nil, err
+	}
+	return bound, nil
+}
+
+func (p pegHandler) Upto(result iter.Seq2[string, any]) (any, error) {
+	_, max := funki.FirstOf(result, "Max")
+	if max == nil {
+		return -1, nil
+	}
+	return max, nil
+}
+
+func (p pegHandler) Min(result iter.Seq2[string, any]) (any, error) {
+	return count(result)
+}
+
+func (p pegHandler) Max(result iter.Seq2[string, any]) (any, error) {
+	return count(result)
+}
+
+func count(digits iter.Seq2[string, any]) (int, error) {
+	var sb strings.Builder
+	for _, digit := range digits {
+		sb.WriteString(digit.(string))
+	}
+	return strconv.Atoi(sb.String())
+}
+
+func (p pegHandler) RecoverExpr(result iter.Seq2[string, any]) (any, error) {
+	exprs := funki.ListOf[Expr](result, "Primary")
+	return Recover(exprs[0], exprs[1]), nil
+}
+
 func (p pegHandler) Primary(result iter.Seq2[string, any]) (any, error) {
-	_, value := funki.FirstOf(result, "Dot", "ParExpr", "Literal", "CharClass", "Ref")
+	_, value := funki.FirstOf(result, "Dot", "Cut", "ParExpr", "CaptureExpr", "BindExpr", "BackRef", "Literal", "CharClass", "Ref")
 	return value, nil
 }
 
@@ -93,13 +224,36 @@ func (p pegHandler) Dot(result iter.Seq2[string, any]) (any, error) {
 	return Dot(), nil
 }
 
+func (p pegHandler) Cut(result iter.Seq2[string, any]) (any, error) {
+	return Cut(), nil
+}
+
 func (p pegHandler) ParExpr(result iter.Seq2[string, any]) (any, error) {
 	_, expr := funki.FirstOf(result, "Expr")
 	return expr, nil
 }
 
+func (p pegHandler) CaptureExpr(result iter.Seq2[string, any]) (any, error) {
+	_, expr := funki.FirstOf(result, "Expr")
+	return Capture(expr.(Expr)), nil
+}
+
+func (p pegHandler) BindExpr(result iter.Seq2[string, any]) (any, error) {
+	_, name := funki.FirstOf(result, "Name")
+	_, expr := funki.FirstOf(result, "Expr")
+	return Bind(name.(string), expr.(Expr)), nil
+}
+
+func (p pegHandler) BackRef(result iter.Seq2[string, any]) (any, error) {
+	_, name := funki.FirstOf(result, "Name")
+	return BackRef(name.(string)), nil
+}
+
 func (p pegHandler) Literal(result iter.Seq2[string, any]) (any, error) {
 	_, value := funki.FirstOf(result, "SingleLit", "DoubleLit")
+	if _, nocase := funki.FirstOf(result, "NoCase"); nocase != nil {
+		return LitI(value.(string)), nil
+	}
 	return Lit(value.(string)), nil
 }
 
@@ -149,10 +303,19 @@ func (p pegHandler) DoubleLit(results iter.Seq2[string, any]) (any, error) {
 
 func (p pegHandler) CharClass(result iter.Seq2[string, any]) (any, error) {
 	_, pattern := funki.FirstOf(result, "Pattern")
+	if _, nocase := funki.FirstOf(result, "NoCase"); nocase != nil {
+		return Cls("(?i)" + pattern.(string)), nil
+	}
 	return Cls(pattern.(string)), nil
 }
 
 func (p pegHandler) Ref(result iter.Seq2[string, any]) (any, error) {
 	_, name := funki.FirstOf(result, "Name")
-	return Ref(name.(string)), nil
+	_, args := funki.FirstOf(result, "Args")
+	exprs, _ := args.([]Expr)
+	return Ref(name.(string), exprs...), nil
+}
+
+func (p pegHandler) Args(result iter.Seq2[string, any]) (any, error) {
+	return funki.ListOf[Expr](result, "Expr"), nil
 }
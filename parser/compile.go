@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/fuwjax/gopase/funki"
+)
+
+/*
+Compiles the grammar for parsing. Every reference is resolved to its rule
+once, instead of by name each time it is parsed, and the results of each rule
+get a memo id, so positions memoize by id instead of by key, and
+generated parsers apply rules by id.
+Parameterized rules are instantiated once for each set of arguments.
+References to undefined rules, or with the wrong number of arguments, fail to
+compile, as do rules instantiating themselves with ever longer arguments.
+
+The compiled grammar is a copy. Rules added to either grammar afterwards are
+not seen by the other.
+*/
+func (g *Grammar) Compile() (*Grammar, error) {
+	compiled := &Grammar{make(map[string]*Rule, len(g.rules)), slices.Clone(g.order), maps.Clone(g.imported), nil, make(map[string]int), nil}
+	for name, rule := range g.Rules() {
+		compiled.rules[name] = &Rule{rule.name, rule.expr, rule.description, rule.params, rule.lexical}
+	}
+	c := &grammarCompiler{compiled, make(map[string]*Rule), nil, nil}
+	for name, rule := range compiled.Rules() {
+		if len(rule.params) > 0 {
+			continue
+		}
+		if _, ok := compiled.ids[name]; !ok {
+			c.assign(&Reference{name, nil, &resolved{rule, len(compiled.ids), name}})
+		}
+		expr, err := c.resolve(rule.expr)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", name, err)
+		}
+		rule.expr = expr
+	}
+	if g.skip != nil {
+		skip, err := c.resolve(g.skip)
+		if err != nil {
+			return nil, fmt.Errorf("skip: %w", err)
+		}
+		compiled.skip = skip
+	}
+	for len(c.pending) > 0 {
+		instance := c.pending[0].rule
+		c.current = c.pending[0].from
+		c.pending = c.pending[1:]
+		expr, err := c.resolve(instance.expr)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", instance.name, err)
+		}
+		instance.expr = expr
+	}
+	return compiled, nil
+}
+
+/*
+Compiles a grammar. Current is the instance of a parameterized rule being
+resolved, if any.
+*/
+type grammarCompiler struct {
+	grammar   *Grammar
+	instances map[string]*Rule
+	pending   []pendingInstance
+	current   *instantiation
+}
+
+/*
+An instance of a parameterized rule waiting to be resolved.
+*/
+type pendingInstance struct {
+	rule *Rule
+	from *instantiation
+}
+
+/*
+Returns a copy of expr with every reference resolved.
+*/
+func (c *grammarCompiler) resolve(expr Expr) (Expr, error) {
+	var failure error
+	expr = transform(expr, func(expr Expr) Expr {
+		ref, ok := expr.(*Reference)
+		if !ok || failure != nil {
+			return expr
+		}
+		resolved, err := c.reference(ref)
+		if err != nil {
+			failure = err
+			return expr
+		}
+		return &Reference{ref.name, ref.args, resolved}
+	})
+	return expr, failure
+}
+
+/*
+Resolves a reference whose arguments are already resolved. The first
+reference to each instance of a parameterized rule creates it, queueing its
+expression to be resolved in turn.
+*/
+func (c *grammarCompiler) reference(ref *Reference) (*resolved, error) {
+	key := ref.key()
+	rule := c.grammar.rules[ref.name]
+	if rule == nil {
+		rule = builtins[ref.name]
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("no such rule: %s", ref.name)
+	}
+	if len(ref.args) > 0 || len(rule.params) > 0 {
+		instance, ok := c.instances[key]
+		if !ok {
+			from, err := c.current.instantiate(ref)
+			if err != nil {
+				return nil, err
+			}
+			if instance, err = rule.instantiate(ref.args); err != nil {
+				return nil, err
+			}
+			c.instances[key] = instance
+			c.pending = append(c.pending, pendingInstance{instance, from})
+		}
+		rule = instance
+	}
+	id, ok := c.grammar.ids[key]
+	if !ok {
+		c.assign(&Reference{ref.name, ref.args, &resolved{rule, len(c.grammar.ids), key}})
+		return c.grammar.refs[len(c.grammar.refs)-1].resolved, nil
+	}
+	return &resolved{rule, id, key}, nil
+}
+
+/*
+Gives the reference's key the next memo id, keeping the reference so the
+rule can be applied by id.
+*/
+func (c *grammarCompiler) assign(ref *Reference) {
+	c.grammar.ids[ref.resolved.key] = ref.resolved.id
+	c.grammar.refs = append(c.grammar.refs, ref)
+}
+
+/*
+An instance of a parameterized rule, with the instance it was first referenced
+from, or nil if it was referenced from a plain rule.
+*/
+type instantiation struct {
+	name, key string
+	args      []string
+	from      *instantiation
+}
+
+/*
+Returns the instance a reference from this one makes. Fails if an instance of
+the same rule this one was referenced from, however indirectly, has arguments
+that the new ones contain and outgrow. That rule would reference an instance
+with longer arguments again each time it was instantiated, without end.
+*/
+func (i *instantiation) instantiate(ref *Reference) (*instantiation, error) {
+	args := funki.Apply(ref.args, Expr.String)
+	for at := i; at != nil; at = at.from {
+		if at.name == ref.name && outgrows(args, at.args) {
+			return nil, fmt.Errorf("%s instantiates %s, and so on without end", at.key, ref.key())
+		}
+	}
+	return &instantiation{ref.name, ref.key(), args, i}, nil
+}
+
+/*
+Returns true if every argument contains the earlier one, and one is longer.
+*/
+func outgrows(args, earlier []string) bool {
+	if len(args) != len(earlier) {
+		return false
+	}
+	longer := false
+	for i, arg := range args {
+		if !strings.Contains(arg, earlier[i]) {
+			return false
+		}
+		longer = longer || len(arg) > len(earlier[i])
+	}
+	return longer
+}
@@ -0,0 +1,115 @@
+package parser_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+func TestCompile(t *testing.T) {
+	peg := when.YouErr(os.ReadFile("../sample/peg.peg")).ExpectSuccess(t)
+	compiled := when.YouErr(parser.PegGrammar().Compile()).ExpectSuccess(t)
+	when.You(compiled.String()).Expect(t, parser.PegGrammar().String())
+	for _, input := range []string{string(peg), skippedJson, "S = 'a' / ", "%import"} {
+		expected, expectedErr := parser.Parse("Grammar", parser.PegGrammar(), parser.PegHandler, input)
+		actual, actualErr := parser.Parse("Grammar", compiled, parser.PegHandler, input)
+		if expectedErr != nil {
+			when.You(actualErr).ExpectMatch(t, func(t *testing.T, actual error) bool {
+				return when.AssertError(t, actual, expectedErr.Error())
+			})
+			continue
+		}
+		when.You(actualErr).Expect(t, nil)
+		when.You(actual.(*parser.Grammar).String()).Expect(t, expected.(*parser.Grammar).String())
+	}
+}
+
+func TestCompileRules(t *testing.T) {
+	grammar := when.YouErr(parser.Bootstrap(`
+%skip = ' '
+S = L List<N, ','> List<N, ';'>? INDENT? 'x'?
+L = L 'a' / 'a'
+List<X, Sep> = '[' X (Sep X)* ']'
+N = [0-9]+
+%lexical N
+`)).ExpectSuccess(t)
+	compiled := when.YouErr(grammar.Compile()).ExpectSuccess(t)
+	for _, input := range []string{"aa [1, 22]", "a[1][2;3]", "a [1,]", "[1]"} {
+		expected, expectedErr := parser.Parse("S", grammar, parser.TreeHandler, input)
+		actual, actualErr := parser.Parse("S", compiled, parser.TreeHandler, input)
+		when.You(actual).Expect(t, expected)
+		when.You(actualErr).Expect(t, expectedErr)
+	}
+	grammar.AddRule("T", parser.Lit("t"))
+	when.YouErr(parser.Parse("T", compiled, parser.TreeHandler, "t")).ExpectError(t, "no such rule: T")
+}
+
+func TestCompileErrors(t *testing.T) {
+	compile := func(grammar string) (*parser.Grammar, error) {
+		return when.YouErr(parser.Bootstrap(grammar)).ExpectSuccess(t).Compile()
+	}
+	when.YouErr(compile("S = 'a' T")).ExpectError(t, "rule S: no such rule: T")
+	when.YouErr(compile("S = List<'a'>\nList<X, Y> = X Y")).ExpectError(t, "rule S: rule List takes 2 arguments, got 1")
+	when.YouErr(compile("%skip = WS\nS = 'a'")).ExpectError(t, "skip: no such rule: WS")
+}
+
+func TestCompileEndless(t *testing.T) {
+	from := parser.NewParserFrom("S = L<'a'>\nL<X> = X / L<(X X)>", nil)
+	when.YouErr(from("S", "a")).ExpectError(t, "rule L: L<Lit(`a`)> instantiates L<Seq(Lit(`a`), Lit(`a`))>, and so on without end")
+	pair := when.YouErr(parser.Bootstrap("S = P<'a', 'b'>\nP<X, Y> = X Y / P<(X Y), Y>")).ExpectSuccess(t)
+	when.YouErr(parser.NewProgram(pair)).ExpectError(t, "rule P: P<Lit(`a`), Lit(`b`)> instantiates P<Seq(Lit(`a`), Lit(`b`)), Lit(`b`)>, and so on without end")
+
+	finite := parser.NewParserFrom("S = L<'a', 'b'>\nL<X, Y> = X / L<Y, X> / L<'aaa', Y>", nil)
+	when.YouErr(finite("S", "b")).Expect(t, "b")
+}
+
+func benchInput() string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := range 500 {
+		if i > 0 {
+			sb.WriteString(", # item\n")
+		}
+		fmt.Fprintf(&sb, `{"id": %d, "name": "item %d", "tags": ["a", "b"], "price": -%d}`, i, i, i)
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+func BenchmarkParse(b *testing.B) {
+	grammar, _ := parser.Bootstrap(skippedJson)
+	input := benchInput()
+	for b.Loop() {
+		parser.Parse("Doc", grammar, parser.WrapHandler(nil), input)
+	}
+}
+
+func BenchmarkParseCompiled(b *testing.B) {
+	grammar, _ := parser.Bootstrap(skippedJson)
+	compiled, _ := grammar.Compile()
+	input := benchInput()
+	for b.Loop() {
+		parser.Parse("Doc", compiled, parser.WrapHandler(nil), input)
+	}
+}
+
+func BenchmarkParsePeg(b *testing.B) {
+	peg, _ := os.ReadFile("../funki/funki.peg")
+	input := string(peg)
+	for b.Loop() {
+		parser.Parse("Grammar", parser.PegGrammar(), parser.PegHandler, input)
+	}
+}
+
+func BenchmarkParsePegCompiled(b *testing.B) {
+	compiled, _ := parser.PegGrammar().Compile()
+	peg, _ := os.ReadFile("../funki/funki.peg")
+	input := string(peg)
+	for b.Loop() {
+		parser.Parse("Grammar", compiled, parser.PegHandler, input)
+	}
+}
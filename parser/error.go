@@ -0,0 +1,236 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fuwjax/gopase/funki"
+)
+
+/*
+ParseError describes a failed parse. Line and Column are 1-based, Offset is
+the byte offset into the input. Expected is the set of things that would have
+let the parse continue, and Rules is the stack of rules active at the failure,
+innermost first. Cause holds any underlying error, such as one returned by a
+Converter.
+*/
+type ParseError struct {
+	Line, Column, Offset int
+	Expected             []string
+	Rules                []string
+	Cause                error
+}
+
+/*
+Creates a ParseError at the position.
+*/
+func newParseError(at *ParsePosition, cause error, expected ...string) *ParseError {
+	return &ParseError{at.grapheme.Line, at.grapheme.Column, at.offset, expected, nil, cause}
+}
+
+/*
+Returns err as a ParseError, wrapping it at the position if it is not one already.
+*/
+func asParseError(err error, at *ParsePosition) *ParseError {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return pe
+	}
+	return newParseError(at, err)
+}
+
+/*
+Returns a copy of this error with the rule pushed onto the rule stack. Errors
+are shared through the packrat cache, so they are never modified in place.
+*/
+func (e *ParseError) within(rule string) *ParseError {
+	copied := *e
+	copied.Rules = append(slices.Clip(e.Rules), rule)
+	return &copied
+}
+
+/*
+Returns the error that got farther into the input, merging the expected sets
+when both failed at the same offset.
+*/
+func (e *ParseError) merge(other *ParseError) *ParseError {
+	switch {
+	case other == nil:
+		return e
+	case e == nil || other.Offset > e.Offset:
+		return other
+	case other.Offset < e.Offset || e.Cause != nil || other.Cause != nil:
+		return e
+	}
+	merged := *e
+	merged.Rules = outerRules(e.Rules, other.Rules)
+	merged.Expected = slices.Clip(e.Expected)
+	for _, expected := range other.Expected {
+		if !slices.Contains(merged.Expected, expected) {
+			merged.Expected = append(merged.Expected, expected)
+		}
+	}
+	return &merged
+}
+
+/*
+Returns the outermost rules shared by both rule stacks.
+*/
+func outerRules(a, b []string) []string {
+	i, j := len(a), len(b)
+	for i > 0 && j > 0 && a[i-1] == b[j-1] {
+		i--
+		j--
+	}
+	return a[i:]
+}
+
+/*
+Returns true for errors that did not come from a failed match, like a missing
+rule or a Converter failure. These are reported as-is instead of the farthest
+failure.
+*/
+func (e *ParseError) hard() bool {
+	return e.Cause != nil && !errors.Is(e.Cause, errLeftRecursion)
+}
+
+var errCommitted = errors.New("committed by cut")
+
+/*
+Returns err as a hard error after a cut. Plain match failures keep their
+position and expectations; any other error is returned as-is.
+*/
+func commit(err error, at *ParsePosition) error {
+	pe := asParseError(err, at)
+	if pe.Cause != nil {
+		return err
+	}
+	committed := *pe
+	committed.Cause = errCommitted
+	return &committed
+}
+
+/*
+Returns true for a match failure committed by a cut. Recovery may still skip
+past these.
+*/
+func (e *ParseError) committed() bool {
+	return errors.Is(e.Cause, errCommitted)
+}
+
+/*
+Returns true if err is a hard ParseError, which stops the parse instead of
+letting it backtrack.
+*/
+func IsHard(err error) bool {
+	var pe *ParseError
+	return errors.As(err, &pe) && pe.hard()
+}
+
+func (e *ParseError) Error() string {
+	var sb strings.Builder
+	switch {
+	case len(e.Expected) == 1:
+		fmt.Fprintf(&sb, "at %d:%d expected %s", e.Line, e.Column, e.Expected[0])
+	case len(e.Expected) > 1:
+		fmt.Fprintf(&sb, "at %d:%d expected one of %s", e.Line, e.Column, strings.Join(e.Expected, ", "))
+	case e.Cause != nil:
+		sb.WriteString(e.Cause.Error())
+	default:
+		fmt.Fprintf(&sb, "at %d:%d", e.Line, e.Column)
+	}
+	for _, rule := range e.Rules {
+		sb.WriteString("\nwhile in ")
+		sb.WriteString(rule)
+	}
+	return sb.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Cause
+}
+
+/*
+Tracks the farthest position any terminal failed to match, and everything
+that was expected there.
+*/
+type farthest struct {
+	err *ParseError
+}
+
+/*
+Records an expectation. Anything short of the current farthest position is
+ignored.
+*/
+func (f *farthest) record(at *ParsePosition, rules *ruleStack, expected string) {
+	failure := newParseError(at, nil, expected)
+	for r := rules; r != nil; r = r.next {
+		failure.Rules = append(failure.Rules, r.name)
+	}
+	f.err = f.err.merge(failure)
+}
+
+/*
+Returns the error to report for a failed parse. A failure committed by a cut
+still reports everything expected there.
+*/
+func (f *farthest) report(err error) error {
+	var pe *ParseError
+	if f.err == nil || errors.As(err, &pe) && pe.hard() && (!pe.committed() || pe.Offset > f.err.Offset) {
+		return err
+	}
+	return f.err
+}
+
+/*
+ErrorKey is the result name given to an ErrorNode, so handlers can pick out
+recovered failures from the rest of the results.
+*/
+const ErrorKey = "!error"
+
+/*
+ErrorNode stands in for a region of input that failed to parse and was
+skipped during recovery. Text is the skipped input.
+*/
+type ErrorNode struct {
+	Err  *ParseError
+	Text string
+}
+
+func (n *ErrorNode) String() string {
+	return fmt.Sprintf("Error(%q)", n.Text)
+}
+
+/*
+Diagnostics collects every failure from a recovering parse, in input order.
+*/
+type Diagnostics []*ParseError
+
+func (d Diagnostics) Error() string {
+	return strings.Join(funki.Apply(d, (*ParseError).Error), "\n")
+}
+
+/*
+PolyError was the error of a failed Options, listing the failure of every
+alternative.
+
+Deprecated: a failed parse now returns a *ParseError holding the farthest
+failure, with the expectations of every alternative that got that far merged
+into Expected. Nothing in the parser returns a PolyError any more.
+*/
+type PolyError struct {
+	Errors []error
+}
+
+/*
+Deprecated: see PolyError.
+*/
+func (es *PolyError) Add(e error) {
+	es.Errors = append(es.Errors, e)
+}
+
+func (es *PolyError) Error() string {
+	return strings.Join(funki.Apply(es.Errors, error.Error), "\n")
+}
@@ -0,0 +1,112 @@
+package parser_test
+
+import (
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+func parseError(err error) (*parser.ParseError, bool) {
+	var pe *parser.ParseError
+	ok := errors.As(err, &pe)
+	return pe, ok
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	grammar := parser.NewGrammar()
+	grammar.AddRule("S", parser.Seq(parser.Lit("é\n"), parser.Ref("T")))
+	grammar.AddRule("T", parser.Lit("ab"))
+	parse := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(nil))
+
+	_, err := parse("é\nax")
+	pe := when.YouOk(parseError(err)).ExpectSuccess(t)
+	when.You(pe.Line).Expect(t, 2)
+	when.You(pe.Column).Expect(t, 1)
+	when.You(pe.Offset).Expect(t, 3)
+	when.You(pe.Expected).Expect(t, []string{"'ab'"})
+	when.You(pe.Rules).Expect(t, []string{"T", "S"})
+	when.You(pe.Cause).Expect(t, nil)
+}
+
+func TestParseErrorAlternatives(t *testing.T) {
+	grammar := parser.NewGrammar()
+	grammar.AddRule("S", parser.Alt(parser.Lit("a"), parser.Lit("b")))
+	parse := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(nil))
+
+	_, err := parse("c")
+	pe := when.YouOk(parseError(err)).ExpectSuccess(t)
+	when.You(pe.Rules).Expect(t, []string{"S"})
+	when.You(pe.Expected).Expect(t, []string{"'a'", "'b'"})
+	when.You(pe.Error()).Expect(t, "at 1:1 expected one of 'a', 'b'\nwhile in S")
+}
+
+func TestParseErrorFarthest(t *testing.T) {
+	grammar := parser.NewGrammar()
+	grammar.AddRule("S", parser.Alt(parser.Seq(parser.Lit("a"), parser.Ref("T")), parser.Lit("a")))
+	grammar.AddRule("T", parser.Seq(parser.Lit("b"), parser.Alt(parser.Lit(","), parser.Lit("}"))))
+	grammar.AddRule("U", parser.Seq(parser.Ref("S"), parser.Not(parser.Dot())))
+	parse := parser.BootstrapParser[any]("U", grammar, parser.WrapHandler(nil))
+
+	_, err := parse("ab]")
+	pe := when.YouOk(parseError(err)).ExpectSuccess(t)
+	when.You(pe.Offset).Expect(t, 2)
+	when.You(pe.Error()).Expect(t, "at 1:3 expected one of ',', '}'\nwhile in T\nwhile in S\nwhile in U")
+}
+
+func TestParseErrorMissingRule(t *testing.T) {
+	grammar := parser.NewGrammar().AddRule("S", parser.Ref("T"))
+	parse := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(nil))
+
+	_, err := parse("a")
+	pe := when.YouOk(parseError(err)).ExpectSuccess(t)
+	when.You(pe.Rules).Expect(t, []string{"S"})
+	when.You(pe.Error()).Expect(t, "no such rule: T\nwhile in S")
+
+	grammar = parser.NewGrammar().AddRule("S", parser.Seq(parser.Alt(parser.Rep(parser.Ref("T")), parser.Lit("b")), parser.Lit("a")))
+	parse = parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(nil))
+	when.YouErr(parse("a")).ExpectError(t, "no such rule: T\nwhile in S")
+}
+
+func TestParseErrorConverter(t *testing.T) {
+	handler := map[string]parser.Converter{"S": func(result iter.Seq2[string, any]) (any, error) {
+		return nil, errors.New("bad S")
+	}}
+	grammar := parser.NewGrammar().AddRule("S", parser.Lit("a"))
+	parse := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(handler))
+
+	_, err := parse("a")
+	pe := when.YouOk(parseError(err)).ExpectSuccess(t)
+	when.You(pe.Offset).Expect(t, 0)
+	when.You(pe.Error()).Expect(t, "bad S\nwhile in S")
+}
+
+func TestParseErrorHardThroughChoices(t *testing.T) {
+	handler := map[string]parser.Converter{"T": func(result iter.Seq2[string, any]) (any, error) {
+		return nil, errors.New("bad T")
+	}}
+	grammar := parser.NewGrammar().AddRule("S", parser.Seq(parser.Alt(parser.Ref("T"), parser.Lit("a")), parser.Opt(parser.Ref("T")), parser.Not(parser.Ref("T"))))
+	grammar.AddRule("T", parser.Lit("a"))
+	parse := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(handler))
+	when.YouErr(parse("a")).ExpectError(t, "bad T\nwhile in T\nwhile in S")
+
+	grammar = parser.NewGrammar().AddRule("S", parser.Seq(parser.Rep(parser.Ref("T")), parser.Lit("b")))
+	grammar.AddRule("T", parser.Lit("a"))
+	parse = parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(handler))
+	when.YouErr(parse("b")).Expect(t, "b")
+	when.YouErr(parse("ab")).ExpectError(t, "bad T\nwhile in T\nwhile in S")
+}
+
+func TestParseErrorDescription(t *testing.T) {
+	grammar, err := parser.Bootstrap(`
+Pair = Number ',' Number
+Number "a number" = '-'? [0-9]+
+`)
+	when.YouErr(grammar, err).ExpectSuccess(t)
+	parse := parser.BootstrapParser[any]("Pair", grammar, parser.WrapHandler(nil))
+
+	when.YouErr(parse("12,-x")).ExpectError(t, "at 1:4 expected a number\nwhile in Pair")
+	when.YouErr(parse("12;3")).ExpectError(t, "at 1:3 expected ','\nwhile in Pair")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/fuwjax/gopase/funki"
 )
@@ -19,10 +20,21 @@ func Seq(exprs ...Expr) Expr {
 	return &Sequence{exprs}
 }
 
+/*
+Matches each expression in turn. Once a Cut in the sequence has matched, a
+failure of a later expression is committed, so enclosing choices do not
+backtrack.
+*/
 func (x *Sequence) Parse(context *ParseContext) (*ParseResult, error) {
+	outer := context.cut
+	context.cut = false
+	defer func() { context.cut = outer }()
 	var result *ParseResult
 	for _, expr := range x.exprs {
 		res, err := expr.Parse(context)
+		if err != nil && context.cut {
+			return nil, context.Commit(err)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -46,34 +58,20 @@ func Alt(exprs ...Expr) Expr {
 	return &Options{exprs}
 }
 
-/*
-Allows for multiple "causes" to aggregate into a single error. This isn't really helping with parsing
-error reporting, so don't depend on it.
-*/
-type PolyError struct {
-	Errors []error
-}
-
-func (es *PolyError) Add(e error) {
-	es.Errors = append(es.Errors, e)
-}
-
-func (es *PolyError) Error() string {
-	return strings.Join(funki.Apply(es.Errors, error.Error), "\n")
-}
-
 func (x *Options) Parse(context *ParseContext) (*ParseResult, error) {
 	mark := context.Mark()
-	var poly PolyError
+	var failure error
 	for _, expr := range x.exprs {
 		result, err := expr.Parse(context)
 		if err == nil {
 			return result, nil
 		}
-		poly.Add(err)
-		context.Reset(mark)
+		if IsHard(err) {
+			return nil, err
+		}
+		failure = context.Backtrack(failure, err, mark)
 	}
-	return nil, &poly
+	return nil, failure
 }
 
 func (x *Options) String() string {
@@ -91,6 +89,9 @@ func Opt(expr Expr) Expr {
 func (x *Optional) Parse(context *ParseContext) (*ParseResult, error) {
 	mark := context.Mark()
 	result, err := x.expr.Parse(context)
+	if IsHard(err) {
+		return nil, err
+	}
 	if err != nil {
 		context.Reset(mark)
 		return nil, nil
@@ -115,6 +116,9 @@ func (x *Repeated) Parse(context *ParseContext) (*ParseResult, error) {
 	for {
 		mark := context.Mark()
 		result, err := x.expr.Parse(context)
+		if IsHard(err) {
+			return nil, err
+		}
 		if err != nil || context.At(mark) {
 			context.Reset(mark)
 			break
@@ -146,6 +150,9 @@ func (x *Required) Parse(context *ParseContext) (*ParseResult, error) {
 	for {
 		mark := context.Mark()
 		result, err = x.expr.Parse(context)
+		if IsHard(err) {
+			return nil, err
+		}
 		if err != nil || context.At(mark) {
 			context.Reset(mark)
 			break
@@ -159,6 +166,62 @@ func (x *Required) String() string {
 	return fmt.Sprintf("Req(%s)", x.expr)
 }
 
+type Bounded struct {
+	expr     Expr
+	min, max int
+}
+
+/*
+Matches expr at least min and at most max times. A negative max has no upper
+bound. Like Rep, it stops once expr matches without consuming input. A min
+more than max is rejected by Validate, and fails every parse.
+*/
+func Bound(expr Expr, min, max int) Expr {
+	return &Bounded{expr, min, max}
+}
+
+/*
+Returns an error if the bounds can never be met.
+*/
+func (x *Bounded) check() error {
+	if x.max >= 0 && x.min > x.max {
+		return fmt.Errorf("min %d is more than max %d", x.min, x.max)
+	}
+	return nil
+}
+
+func (x *Bounded) Parse(context *ParseContext) (*ParseResult, error) {
+	if err := x.check(); err != nil {
+		return nil, err
+	}
+	start := context.Mark()
+	var agg *ParseResult
+	for count := 0; x.max < 0 || count < x.max; count++ {
+		mark := context.Mark()
+		result, err := x.expr.Parse(context)
+		if IsHard(err) {
+			return nil, err
+		}
+		if err != nil && count < x.min {
+			context.Reset(start)
+			return nil, err
+		}
+		if err != nil {
+			context.Reset(mark)
+			break
+		}
+		agg = agg.Chain(result)
+		if context.At(mark) {
+			break
+		}
+	}
+	return agg, nil
+}
+
+func (x *Bounded) String() string {
+	return fmt.Sprintf("Bound(%s, %d, %d)", x.expr, x.min, x.max)
+}
+
 type CharClass struct {
 	regex *regexp.Regexp
 }
@@ -168,12 +231,16 @@ func Cls(pattern string) Expr {
 }
 
 func (x *CharClass) Parse(context *ParseContext) (*ParseResult, error) {
+	if err := context.skip(); err != nil {
+		return nil, err
+	}
+	mark := context.Mark()
 	token := context.Token()
 	if !x.regex.MatchString(token) {
-		return nil, context.Error(x.regex.String())
+		return nil, context.trace(x, mark, context.Error(x.regex.String()))
 	}
 	err := context.Next()
-	return NewResult("", token), err
+	return NewResult("", token), context.trace(x, mark, err)
 }
 
 func (x *CharClass) String() string {
@@ -189,22 +256,61 @@ func Lit(literal string) Expr {
 }
 
 func (x *Literal) Parse(context *ParseContext) (*ParseResult, error) {
-	for ch := range Graphemes(x.literal) {
-		if ch.Token != context.Token() {
-			return nil, context.Error(ch.Token)
-		}
-		err := context.Next()
-		if err != nil {
-			return nil, err
-		}
+	if err := context.skip(); err != nil {
+		return nil, err
+	}
+	mark := context.Mark()
+	if !context.match(x.literal, sameText) {
+		return nil, context.trace(x, mark, context.fail(mark, quote(x.literal)))
 	}
-	return NewResult("", x.literal), nil
+	return NewResult("", x.literal), context.trace(x, mark, nil)
+}
+
+func sameText(a, b string) bool {
+	return a == b
+}
+
+var quoteEscapes = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+/*
+Quotes a literal the way the PEG syntax would, for error messages.
+*/
+func quote(literal string) string {
+	return "'" + quoteEscapes.Replace(literal) + "'"
 }
 
 func (x *Literal) String() string {
 	return fmt.Sprintf("Lit(`%s`)", x.literal)
 }
 
+type FoldedLiteral struct {
+	literal string
+}
+
+/*
+Matches the literal regardless of case, comparing graphemes with Unicode simple
+case folding, as strings.EqualFold does. Full case folding is not applied, so
+'ß'i does not match SS. The result is the matched input, not the literal.
+*/
+func LitI(literal string) Expr {
+	return &FoldedLiteral{literal}
+}
+
+func (x *FoldedLiteral) Parse(context *ParseContext) (*ParseResult, error) {
+	if err := context.skip(); err != nil {
+		return nil, err
+	}
+	mark := context.Mark()
+	if !context.match(x.literal, strings.EqualFold) {
+		return nil, context.trace(x, mark, context.fail(mark, quote(x.literal)+"i"))
+	}
+	return NewResult("", context.Substring(mark)), context.trace(x, mark, nil)
+}
+
+func (x *FoldedLiteral) String() string {
+	return fmt.Sprintf("LitI(`%s`)", x.literal)
+}
+
 type Any struct{}
 
 func Dot() Expr {
@@ -212,51 +318,310 @@ func Dot() Expr {
 }
 
 func (x *Any) Parse(context *ParseContext) (*ParseResult, error) {
+	if err := context.skip(); err != nil {
+		return nil, err
+	}
+	mark := context.Mark()
 	token := context.Token()
 	err := context.Next()
-	return NewResult("", token), err
+	return NewResult("", token), context.trace(x, mark, err)
 }
 
 func (x *Any) String() string {
 	return "Dot()"
 }
 
+type Commit struct{}
+
+/*
+Matches nothing and commits the enclosing sequence: if anything after the cut
+fails, the parse fails there instead of trying other alternatives. Memoized
+results behind the cut are released.
+*/
+func Cut() Expr {
+	return &Commit{}
+}
+
+func (x *Commit) Parse(context *ParseContext) (*ParseResult, error) {
+	context.cut = true
+	context.release()
+	return nil, context.trace(x, context.Mark(), nil)
+}
+
+func (x *Commit) String() string {
+	return "Cut()"
+}
+
 type Reference struct {
-	name string
+	name     string
+	args     []Expr
+	resolved *resolved
 }
 
-func Ref(name string) Expr {
-	return &Reference{name}
+/*
+What Grammar.Compile resolved a reference to: the rule, with any arguments
+already applied, and the memo id and key of its results.
+*/
+type resolved struct {
+	rule *Rule
+	id   int
+	key  string
+}
+
+/*
+References a rule by name. Arguments instantiate a parameterized rule.
+*/
+func Ref(name string, args ...Expr) Expr {
+	return &Reference{name, args, nil}
+}
+
+/*
+Returns the name the results of this reference are cached under, which
+includes the arguments for a parameterized rule.
+*/
+func (x *Reference) key() string {
+	if len(x.args) == 0 {
+		return x.name
+	}
+	return x.name + "<" + strings.Join(funki.Apply(x.args, Expr.String), ", ") + ">"
 }
 
 func (x *Reference) Parse(context *ParseContext) (*ParseResult, error) {
+	return x.apply(context, nil)
+}
+
+/*
+Parses the referenced rule with body in place of its expression, or with its
+expression if body is nil. Skipped input before the rule is not part of its
+match.
+*/
+func (x *Reference) apply(context *ParseContext, body ParseFunc) (*ParseResult, error) {
+	if err := context.skip(); err != nil {
+		return nil, err
+	}
 	mark := context.Mark()
-	result, err, end, cacheHit := mark.get(x.name)
+	var memo memoKey
+	if x.resolved != nil {
+		memo = memoKey{x.resolved.key, x.resolved.id}
+	} else {
+		memo = context.memoKey(x.key())
+	}
+	key := memo.key
+	result, recovered, err, end, cacheHit := mark.get(memo)
+	if context.profile != nil {
+		stats := context.profile.rule(key)
+		stats.Invocations++
+		if cacheHit {
+			stats.MemoHits++
+		} else {
+			stats.MemoMisses++
+			defer func(start time.Time) { stats.Time += time.Since(start) }(time.Now())
+		}
+	}
 	if cacheHit {
+		if context.tracer != nil {
+			context.tracer.MemoHit(key, mark)
+		}
 		if err != nil {
 			return nil, err
 		}
 		context.Reset(end)
 	} else {
-		rule := context.grammar.Rule(x.name)
-		if rule == nil {
-			return nil, fmt.Errorf("no such rule: %s", x.name)
+		rule, err := context.rule(x)
+		if err != nil {
+			return nil, newParseError(mark, err)
+		}
+		if context.tracer != nil {
+			context.tracer.Enter(key, mark)
+		}
+		if rule.description != "" {
+			context.quiet++
 		}
 		recurse := true
 		for recurse {
 			context.Reset(mark)
-			result, err = rule.Parse(context)
-			result, err, recurse = mark.put(x.name, result, err, context.Mark())
+			result, recovered, err = rule.parse(context, body)
+			if err != nil && rule.description != "" && !asParseError(err, mark).hard() {
+				err = newParseError(mark, nil, rule.description)
+			}
+			result, recovered, err, end, recurse = mark.put(memo, result, recovered, err, context.Mark())
+			if recurse && context.profile != nil {
+				context.profile.rule(key).Reseeds++
+			}
+		}
+		if err == nil {
+			context.Reset(end)
+		}
+		if rule.description != "" {
+			context.quiet--
+			if err != nil && !asParseError(err, mark).hard() {
+				context.fail(mark, rule.description)
+			}
+		}
+		if context.tracer != nil {
+			context.tracer.Exit(key, mark, context.Mark(), err)
 		}
 		if err != nil {
 			return nil, err
 		}
 	}
-	return NewResult(x.name, result), nil
+	agg := NewResult(x.name, result)
+	for _, node := range recovered {
+		agg.Chain(NewResult(ErrorKey, node))
+	}
+	return agg, nil
 }
 
 func (x *Reference) String() string {
-	return fmt.Sprintf("Ref(\"%s\")", x.name)
+	if len(x.args) == 0 {
+		return fmt.Sprintf("Ref(\"%s\")", x.name)
+	}
+	return fmt.Sprintf("Ref(\"%s\", %s)", x.name, strings.Join(funki.Apply(x.args, Expr.String), ", "))
+}
+
+type Recovery struct {
+	expr Expr
+	sync Expr
+}
+
+/*
+Matches expr. When recovering, a failure of expr is recorded and the input is
+skipped up to the next match of sync, yielding an ErrorNode in place of the
+result.
+*/
+func Recover(expr Expr, sync Expr) Expr {
+	return &Recovery{expr, sync}
+}
+
+func (x *Recovery) Parse(context *ParseContext) (*ParseResult, error) {
+	return context.Recover(x.expr.Parse, x.sync.Parse)
+}
+
+func (x *Recovery) String() string {
+	return fmt.Sprintf("Recover(%s, %s)", x.expr, x.sync)
+}
+
+type Labeled struct {
+	label string
+	expr  Expr
+}
+
+/*
+Matches expr, yielding a single result under the label. The value is the
+result of expr if it has exactly one, a slice of its results if it has more,
+or nil if it has none, like an optional that did not match.
+*/
+func Label(label string, expr Expr) Expr {
+	return &Labeled{label, expr}
+}
+
+func (x *Labeled) Parse(context *ParseContext) (*ParseResult, error) {
+	result, err := x.expr.Parse(context)
+	if err != nil {
+		return nil, err
+	}
+	return result.Label(x.label), nil
+}
+
+func (x *Labeled) String() string {
+	return fmt.Sprintf("Label(\"%s\", %s)", x.label, x.expr)
+}
+
+/*
+Returns fresh results for the error nodes recovered within result, so they
+can be passed along when the rest of result is replaced.
+*/
+func recoveredOf(result *ParseResult) *ParseResult {
+	var recovered *ParseResult
+	for name, value := range result.Results() {
+		if name == ErrorKey {
+			recovered = recovered.Chain(NewResult(ErrorKey, value))
+		}
+	}
+	return recovered
+}
+
+type Capturing struct {
+	expr Expr
+}
+
+/*
+Matches expr, yielding a single Captured result holding the exact text it
+matched and its span.
+*/
+func Capture(expr Expr) Expr {
+	return &Capturing{expr}
+}
+
+func (x *Capturing) Parse(context *ParseContext) (*ParseResult, error) {
+	if err := context.skip(); err != nil {
+		return nil, err
+	}
+	mark := context.Mark()
+	result, err := x.expr.Parse(context)
+	if err != nil {
+		return nil, err
+	}
+	return context.Captured(mark, result), nil
+}
+
+func (x *Capturing) String() string {
+	return fmt.Sprintf("Capture(%s)", x.expr)
+}
+
+type Binding struct {
+	name string
+	expr Expr
+}
+
+/*
+Matches expr and binds the matched text to the name, so a later BackRef can
+match the same text again. A binding lasts until it is backtracked over or
+the name is bound again.
+*/
+func Bind(name string, expr Expr) Expr {
+	return &Binding{name, expr}
+}
+
+func (x *Binding) Parse(context *ParseContext) (*ParseResult, error) {
+	if err := context.skip(); err != nil {
+		return nil, err
+	}
+	mark := context.Mark()
+	result, err := x.expr.Parse(context)
+	if err != nil {
+		return nil, err
+	}
+	context.Bind(x.name, mark)
+	return result, nil
+}
+
+func (x *Binding) String() string {
+	return fmt.Sprintf("Bind(\"%s\", %s)", x.name, x.expr)
+}
+
+type BackReference struct {
+	name string
+}
+
+/*
+Matches exactly the text last bound to the name. It fails if nothing is bound.
+*/
+func BackRef(name string) Expr {
+	return &BackReference{name}
+}
+
+func (x *BackReference) Parse(context *ParseContext) (*ParseResult, error) {
+	if err := context.skip(); err != nil {
+		return nil, err
+	}
+	mark := context.Mark()
+	result, err := context.BackRef(x.name)
+	return result, context.trace(x, mark, err)
+}
+
+func (x *BackReference) String() string {
+	return fmt.Sprintf("BackRef(\"%s\")", x.name)
 }
 
 type PositiveLookahead struct {
@@ -269,8 +634,10 @@ func See(expr Expr) Expr {
 
 func (x *PositiveLookahead) Parse(context *ParseContext) (*ParseResult, error) {
 	mark := context.Mark()
+	context.Quiet(true)
 	_, err := x.expr.Parse(context)
-	context.Reset(mark) // forces zero length, but only meaningful after a match
+	context.Quiet(false)
+	context.Reset(mark)
 	return nil, err
 }
 
@@ -288,12 +655,27 @@ func Not(expr Expr) Expr {
 
 func (x *NegativeLookahead) Parse(context *ParseContext) (*ParseResult, error) {
 	mark := context.Mark()
+	context.Quiet(true)
 	_, err := x.expr.Parse(context)
+	context.Quiet(false)
 	context.Reset(mark)
+	if IsHard(err) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, nil
 	}
-	return nil, mark.grapheme.Error("not something")
+	return nil, context.fail(mark, x.expected())
+}
+
+/*
+Returns what the lookahead expects to find instead of its expression.
+*/
+func (x *NegativeLookahead) expected() string {
+	if _, ok := x.expr.(*Any); ok {
+		return "end of input"
+	}
+	return "not " + x.expr.String()
 }
 
 func (x *NegativeLookahead) String() string {
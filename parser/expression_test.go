@@ -2,8 +2,11 @@ package parser_test
 
 import (
 	"iter"
+	"runtime"
 	"slices"
+	"strings"
 	"testing"
+	"weak"
 
 	"github.com/fuwjax/gopase/funki"
 	"github.com/fuwjax/gopase/parser"
@@ -27,7 +30,7 @@ func TestParserSeq(t *testing.T) {
 	parser := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(handler))
 
 	when.YouDoErr("seq matching", testParser(parser, "abab")).Expect(t, []any{"ab", "ab"})
-	when.YouDoErr("seq miss", testParser(parser, "abba")).ExpectError(t, "at 'b' 1:3 (3) expected a\nwhile in T\nwhile in S")
+	when.YouDoErr("seq miss", testParser(parser, "abba")).ExpectError(t, "at 1:3 expected 'ab'\nwhile in T\nwhile in S")
 }
 
 func TestParserAlt(t *testing.T) {
@@ -42,7 +45,7 @@ func TestParserAlt(t *testing.T) {
 
 	when.YouDoErr("alt matching repeat", testParser(parser, "abab")).Expect(t, []any{"ab"})
 	when.YouDoErr("alt matching", testParser(parser, "abba")).Expect(t, []any{"ab"})
-	when.YouDoErr("alt miss", testParser(parser, "acba")).ExpectError(t, "at 'c' 1:2 (2) expected b\nwhile in T\nat 'c' 1:2 (2) expected b\nwhile in T\nwhile in S")
+	when.YouDoErr("alt miss", testParser(parser, "acba")).ExpectError(t, "at 1:1 expected 'ab'\nwhile in T\nwhile in S")
 }
 
 func TestParserCls(t *testing.T) {
@@ -51,7 +54,135 @@ func TestParserCls(t *testing.T) {
 	parser := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(nil))
 
 	when.YouDoErr("cls matching", testParser(parser, "a")).Expect(t, "a")
-	when.YouDoErr("cls miss", testParser(parser, "x")).ExpectError(t, "at 'x' 1:1 (1) expected [a-f]\nwhile in S")
+	when.YouDoErr("cls miss", testParser(parser, "x")).ExpectError(t, "at 1:1 expected [a-f]\nwhile in S")
+}
+
+func TestParserLitI(t *testing.T) {
+	grammar := parser.NewGrammar()
+	grammar.AddRule("S", parser.Seq(parser.LitI("select"), parser.Lit(" "), parser.LitI("σ")))
+	sharp := parser.BootstrapParser[any]("S", parser.NewGrammar().AddRule("S", parser.LitI("straße")), parser.WrapHandler(nil))
+	parser := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(nil))
+
+	when.YouDoErr("lit i lower", testParser(parser, "select σ")).Expect(t, "select σ")
+	when.YouDoErr("lit i mixed", testParser(parser, "SeLeCT Σ")).Expect(t, "SeLeCT Σ")
+	when.YouDoErr("lit i final sigma", testParser(parser, "SELECT ς")).Expect(t, "SELECT ς")
+	when.YouDoErr("lit i miss", testParser(parser, "selekt σ")).ExpectError(t, "at 1:1 expected 'select'i\nwhile in S")
+
+	when.YouDoErr("lit i simple folding", testParser(sharp, "STRAßE")).Expect(t, "STRAßE")
+	when.YouDoErr("lit i no full folding", testParser(sharp, "STRASSE")).ExpectError(t, "at 1:1 expected 'straße'i\nwhile in S")
+}
+
+func TestParserBound(t *testing.T) {
+	grammar := parser.NewGrammar()
+	grammar.AddRule("Exact", parser.Seq(parser.Bound(parser.Cls("[0-9]"), 2, 2), parser.Not(parser.Dot())))
+	grammar.AddRule("AtLeast", parser.Seq(parser.Bound(parser.Cls("[0-9]"), 2, -1), parser.Not(parser.Dot())))
+	grammar.AddRule("Between", parser.Seq(parser.Bound(parser.Cls("[0-9]"), 1, 3), parser.Lit(".")))
+	grammar.AddRule("Empty", parser.Seq(parser.Bound(parser.Opt(parser.Lit("a")), 3, 5), parser.Lit("b")))
+	parse := parser.BootstrapParserFrom(grammar, parser.WrapHandler(nil))
+
+	when.YouDoErr("bound exact", func() (any, error) { return parse("Exact", "12") }).Expect(t, "12")
+	when.YouDoErr("bound exact short", func() (any, error) { return parse("Exact", "1") }).ExpectError(t, "at 1:2 expected [0-9]\nwhile in Exact")
+	when.YouDoErr("bound exact long", func() (any, error) { return parse("Exact", "123") }).ExpectError(t, "at 1:3 expected end of input\nwhile in Exact")
+	when.YouDoErr("bound at least", func() (any, error) { return parse("AtLeast", "12345") }).Expect(t, "12345")
+	when.YouDoErr("bound between", func() (any, error) { return parse("Between", "123.") }).Expect(t, "123.")
+	when.YouDoErr("bound between long", func() (any, error) { return parse("Between", "1234.") }).ExpectError(t, "at 1:4 expected '.'\nwhile in Between")
+	when.YouDoErr("bound no progress", func() (any, error) { return parse("Empty", "ab") }).Expect(t, "ab")
+}
+
+func TestParserCut(t *testing.T) {
+	grammar := parser.NewGrammar()
+	grammar.AddRule("Cut", parser.Alt(parser.Seq(parser.Lit("{"), parser.Cut(), parser.Req(parser.Cls("[a-z]")), parser.Lit("}")), parser.Seq(parser.Lit("{"), parser.Req(parser.Cls("[0-9]")), parser.Lit("}"))))
+	grammar.AddRule("NoCut", parser.Alt(parser.Seq(parser.Lit("{"), parser.Req(parser.Cls("[a-z]")), parser.Lit("}")), parser.Seq(parser.Lit("{"), parser.Req(parser.Cls("[0-9]")), parser.Lit("}"))))
+	grammar.AddRule("List", parser.Seq(parser.Lit("["), parser.Rep(parser.Seq(parser.Lit(","), parser.Cut(), parser.Cls("[a-z]"))), parser.Lit("]")))
+	grammar.AddRule("Again", parser.Alt(parser.Seq(parser.Ref("Word"), parser.Lit("!")), parser.Seq(parser.Ref("Word"), parser.Lit("?"))))
+	grammar.AddRule("Word", parser.Seq(parser.Lit("a"), parser.Cut(), parser.Lit("b")))
+	grammar.AddRule("Left", parser.Alt(parser.Seq(parser.Ref("Left"), parser.Lit(","), parser.Cut(), parser.Cls("[a-z]")), parser.Cls("[a-z]")))
+	parse := parser.BootstrapParserFrom(grammar, parser.WrapHandler(nil))
+
+	when.YouDoErr("cut match", func() (any, error) { return parse("Cut", "{ab}") }).Expect(t, "{ab}")
+	when.YouDoErr("cut commits", func() (any, error) { return parse("Cut", "{12}") }).ExpectError(t, "at 1:2 expected [a-z]\nwhile in Cut")
+	when.YouDoErr("no cut backtracks", func() (any, error) { return parse("NoCut", "{12}") }).Expect(t, "{12}")
+	when.YouDoErr("cut in repetition", func() (any, error) { return parse("List", "[,a,1]") }).ExpectError(t, "at 1:5 expected [a-z]\nwhile in List")
+	when.YouDoErr("cut scoped to rule", func() (any, error) { return parse("Again", "ab?") }).Expect(t, "ab?")
+	when.YouDoErr("cut inside rule", func() (any, error) { return parse("Again", "ac") }).ExpectError(t, "at 1:2 expected 'b'\nwhile in Word\nwhile in Again")
+	when.YouDoErr("cut in left recursion", func() (any, error) { return parse("Left", "a,b,c") }).Expect(t, "a,b,c")
+
+	recovering := parser.NewRecoveringParser[string]("S", "S = Item ~> [;] (';' Item ~> [;])*\nItem = '(' ^ [a-z]+ ')'", nil)
+	when.YouErr(recovering("(a);(1);(b)")).ExpectError(t, "at 1:6 expected [a-z]\nwhile in Item\nwhile in S")
+}
+
+/*
+Records weak pointers to the positions it is parsed at, to see which of them
+the parse leaves reachable. If it holds, it also keeps the first position.
+*/
+type markRecorder struct {
+	holds bool
+	first *parser.ParsePosition
+	marks []weak.Pointer[parser.ParsePosition]
+}
+
+func (x *markRecorder) Parse(context *parser.ParseContext) (*parser.ParseResult, error) {
+	if x.holds && x.first == nil {
+		x.first = context.Mark()
+	}
+	x.marks = append(x.marks, weak.Make(context.Mark()))
+	return nil, nil
+}
+
+func (x *markRecorder) String() string {
+	return "Marks()"
+}
+
+func (x *markRecorder) reachable() int {
+	runtime.GC()
+	count := 0
+	for _, mark := range x.marks {
+		if mark.Value() != nil {
+			count++
+		}
+	}
+	return count
+}
+
+func TestParserCutReleases(t *testing.T) {
+	input := "[a" + strings.Repeat(",a", 100) + "]"
+	for cut, reachable := range map[parser.Expr]int{parser.Cut(): 0, parser.Seq(): 101} {
+		start, items := &markRecorder{true, nil, nil}, &markRecorder{}
+		grammar := parser.NewGrammar()
+		grammar.AddRule("List", parser.Seq(start, parser.Ref("Open"), parser.Ref("Item"), parser.Rep(parser.Seq(parser.Ref("Comma"), cut, parser.Ref("Item"))), parser.Lit("]")))
+		grammar.AddRule("Item", parser.Seq(items, parser.Cls("[a-z]")))
+		grammar.AddRule("Open", parser.Lit("["))
+		grammar.AddRule("Comma", parser.Lit(","))
+		when.YouErr(parser.Parse("List", grammar, nil, input)).Expect(t, input)
+		when.You(start.first.Offset()).Expect(t, 0)
+		when.You(items.reachable()).Expect(t, reachable)
+		runtime.KeepAlive(start.first)
+	}
+}
+
+func TestParserBackRef(t *testing.T) {
+	heredoc := parser.NewParser[any]("Doc", `
+Doc = '<<' $tag([A-Z]+) EOL (!(EOL $tag) .)* EOL $tag
+EOL = '\n'
+`, nil)
+	when.YouDoErr("back ref heredoc", testParser(heredoc, "<<END\nhello\nEOF\nEND")).Expect(t, "<<END\nhello\nEOF\nEND")
+	when.YouDoErr("back ref mismatch", testParser(heredoc, "<<END\nhi\nEOT")).ExpectError(t, "at 3:4 expected anything\nwhile in Doc")
+
+	grammar := parser.NewGrammar()
+	grammar.AddRule("Rollback", parser.Alt(parser.Seq(parser.Bind("q", parser.Lit("a")), parser.Lit("x")), parser.Seq(parser.Lit("a"), parser.Ref("Q"))))
+	grammar.AddRule("Memo", parser.Alt(parser.Seq(parser.Bind("q", parser.Lit("ab")), parser.Ref("Q")), parser.Seq(parser.Lit("a"), parser.Bind("q", parser.Lit("b")), parser.Ref("Q"))))
+	grammar.AddRule("Q", parser.BackRef("q"))
+	parse := parser.BootstrapParserFrom(grammar, parser.WrapHandler(nil))
+
+	when.YouDoErr("back ref rolled back", func() (any, error) { return parse("Rollback", "aa") }).ExpectError(t, "at 1:2 expected one of 'x', $q\nwhile in Rollback")
+	when.YouDoErr("back ref memo", func() (any, error) { return parse("Memo", "abb") }).Expect(t, "abb")
+
+	tags := parser.NewParser[any]("Doc", `
+Doc = Tag+ !.
+Tag = '<' $tag([a-z]+) '>' ^ (!('</' $tag '>') .)* '</' $tag '>'
+`, nil)
+	when.YouDoErr("back ref after cut", testParser(tags, "<a>1</a><bb>2</a></bb><a></a>")).Expect(t, "<a>1</a><bb>2</a></bb><a></a>")
+	when.YouDoErr("back ref after cut miss", testParser(tags, "<a>1</a><b>2")).ExpectError(t, "at 1:13 expected one of anything, '</'\nwhile in Tag\nwhile in Doc")
 }
 
 func TestParserDot(t *testing.T) {
@@ -60,7 +191,7 @@ func TestParserDot(t *testing.T) {
 	parser := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(nil))
 
 	when.YouDoErr("dot matching", testParser(parser, "a")).Expect(t, "a")
-	when.YouDoErr("dot eof", testParser(parser, "")).ExpectError(t, "at EOF 1:0 (0) expected anything\nwhile in S")
+	when.YouDoErr("dot eof", testParser(parser, "")).ExpectError(t, "at 1:0 expected anything\nwhile in S")
 }
 
 func TestParserOpt(t *testing.T) {
@@ -88,7 +219,7 @@ func TestParserRep(t *testing.T) {
 	parser := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(handler))
 
 	when.YouDoErr("rep matching", testParser(parser, "aba")).Expect(t, []any{"ab"})
-	when.YouDoErr("rep miss", testParser(parser, "abab")).ExpectError(t, "at EOF 1:5 (5) expected a\nwhile in S")
+	when.YouDoErr("rep miss", testParser(parser, "abab")).ExpectError(t, "at 1:5 expected one of 'ab', 'a'\nwhile in S")
 	when.YouDoErr("rep matching multiple", testParser(parser, "ababa")).Expect(t, []any{"ab", "ab"})
 	when.YouDoErr("rep empty", testParser(parser, "a")).Expect(t, []any(nil))
 }
@@ -104,9 +235,9 @@ func TestParserReq(t *testing.T) {
 	parser := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(handler))
 
 	when.YouDoErr("req matching", testParser(parser, "aba")).Expect(t, []any{"ab"})
-	when.YouDoErr("req miss", testParser(parser, "abab")).ExpectError(t, "at EOF 1:5 (5) expected a\nwhile in S")
+	when.YouDoErr("req miss", testParser(parser, "abab")).ExpectError(t, "at 1:5 expected one of 'ab', 'a'\nwhile in S")
 	when.YouDoErr("req matching multiple", testParser(parser, "ababa")).Expect(t, []any{"ab", "ab"})
-	when.YouDoErr("req empty", testParser(parser, "a")).ExpectError(t, "at EOF 1:2 (2) expected b\nwhile in T\nwhile in S")
+	when.YouDoErr("req empty", testParser(parser, "a")).ExpectError(t, "at 1:1 expected 'ab'\nwhile in T\nwhile in S")
 }
 
 func TestParserSee(t *testing.T) {
@@ -120,7 +251,7 @@ func TestParserSee(t *testing.T) {
 	parser := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(handler))
 
 	when.YouDoErr("see matching", testParser(parser, "aba")).Expect(t, []any(nil))
-	when.YouDoErr("see miss", testParser(parser, "bab")).ExpectError(t, "at 'b' 1:1 (1) expected a\nwhile in T\nwhile in S")
+	when.YouDoErr("see miss", testParser(parser, "bab")).ExpectError(t, "at 1:1 expected 'ab'\nwhile in T\nwhile in S")
 }
 
 func TestParserNot(t *testing.T) {
@@ -134,5 +265,40 @@ func TestParserNot(t *testing.T) {
 	parser := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(handler))
 
 	when.YouDoErr("not matching", testParser(parser, "ababa")).Expect(t, []any{"ab", "ab"})
-	when.YouDoErr("not miss", testParser(parser, "ababaa")).ExpectError(t, "at 'a' 1:6 (6) expected not something\nwhile in S")
+	when.YouDoErr("not miss", testParser(parser, "ababaa")).ExpectError(t, "at 1:6 expected end of input\nwhile in S")
+}
+
+func TestParserRecover(t *testing.T) {
+	handler := make(map[string]parser.Converter)
+	handler["S"] = func(result iter.Seq2[string, any]) (any, error) {
+		return slices.Collect(funki.Values(funki.FilterKeys(result, "T", parser.ErrorKey))), nil
+	}
+	grammar := parser.NewGrammar()
+	grammar.AddRule("S", parser.Seq(parser.Recover(parser.Ref("T"), parser.Lit(";")), parser.Rep(parser.Seq(parser.Lit(";"), parser.Recover(parser.Ref("T"), parser.Lit(";")))), parser.Not(parser.Dot())))
+	grammar.AddRule("T", parser.Lit("ab"))
+	recovering := func(input string) when.WhenOpErr[any] {
+		return func() (any, error) {
+			return parser.Parse("S", grammar, parser.WrapHandler(handler), input, parser.WithRecovery())
+		}
+	}
+	plain := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(handler))
+
+	when.YouDoErr("recover matching", recovering("ab;ab")).Expect(t, []any{"ab", "ab"})
+	when.YouDoErr("recover plain miss", testParser(plain, "ab;xx;ab")).ExpectError(t, "at 1:4 expected 'ab'\nwhile in T\nwhile in S")
+	when.YouDoErr("recover miss", recovering("ab;xx;ab;a")).ExpectError(t, "at 1:4 expected 'ab'\nwhile in T\nwhile in S\nat 1:10 expected 'ab'\nwhile in T\nwhile in S")
+	when.YouDoErr("recover trailing", recovering("ab;ab;")).ExpectError(t, "at 1:7 expected 'ab'\nwhile in T\nwhile in S")
+}
+
+func TestParserRecoverValid(t *testing.T) {
+	grammar := parser.NewGrammar().AddRule("S", parser.Recover(parser.Rep(parser.Lit("a")), parser.Lit(";")))
+	when.YouErr(parser.Parse("S", grammar, parser.WrapHandler(nil), "a", parser.WithRecovery())).Expect(t, "a")
+}
+
+func TestParserRecoverCombined(t *testing.T) {
+	grammar := parser.NewGrammar().AddRule("S", parser.Seq(parser.Recover(parser.Lit("ab"), parser.Lit(";")), parser.Lit(";")))
+	var sb strings.Builder
+	profile := parser.NewProfile()
+	when.YouErr(parser.Parse("S", grammar, parser.WrapHandler(nil), "x;", parser.WithRecovery(), parser.WithTracer(parser.NewTextTracer(&sb)), parser.WithProfile(profile))).ExpectError(t, "at 1:1 expected 'ab'\nwhile in S")
+	when.You(profile.Rule("S").Invocations).Expect(t, 1)
+	when.You(strings.HasPrefix(sb.String(), "S @1:1 {")).Expect(t, true)
 }
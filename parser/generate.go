@@ -0,0 +1,412 @@
+package parser
+
+import (
+	"fmt"
+	"go/format"
+	"slices"
+	"strings"
+
+	"github.com/fuwjax/gopase/funki"
+)
+
+/*
+Generates Go source for an imperative parser of the grammar, with one function
+per rule and no Expr tree to walk. Rules call each other directly, and
+literals and classes are matched inline. The options are those of
+sample.RenderPeg: the package, the name, which prefixes the generated
+functions, and inPackage, set when generating into this package. The source
+declares <name>Imperative() *parser.Grammar, whose rules parse with the
+generated functions, so parsing with it gives exactly the results of parsing
+with the grammar.
+
+Parameterized rules, token rules, skipping, and cuts that are not directly in
+a sequence are not supported.
+*/
+func Generate(grammar *Grammar, opts map[string]any) ([]byte, error) {
+	if grammar.lexed() || grammar.skip != nil || len(grammar.Lexicals()) > 0 {
+		return nil, fmt.Errorf("cannot generate a grammar with %%token, %%ignore, %%lexical or %%skip")
+	}
+	for rule, r := range grammar.Rules() {
+		if len(r.params) > 0 {
+			return nil, fmt.Errorf("cannot generate parameterized rule %s", rule)
+		}
+	}
+	compiled, err := grammar.Compile()
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate %w", err)
+	}
+	name, _ := opts["name"].(string)
+	pkg, _ := opts["package"].(string)
+	inPackage, _ := opts["inPackage"].(bool)
+	gen := &generator{prefix: "parser.", name: "parse" + name, vars: "class", grammar: grammar, ids: compiled.ids}
+	if inPackage {
+		gen.prefix = ""
+	}
+	if name != "" {
+		gen.vars = strings.ToLower(name[:1]) + name[1:] + "Class"
+	}
+	var functions strings.Builder
+	for rule, r := range grammar.Rules() {
+		gen.rule, gen.count = rule, 0
+		body, err := gen.code(r.expr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate rule %s: %w", rule, err)
+		}
+		fmt.Fprintf(&functions, "\nfunc %s(c *%sParseContext) (*%sParseResult, error) {\nreturn c.Apply(%d, %sBody)\n}\n", gen.function(rule), gen.prefix, gen.prefix, compiled.ids[rule], gen.function(rule))
+		functions.WriteString(gen.parseFunc(gen.function(rule)+"Body", body))
+		for _, aux := range gen.aux {
+			functions.WriteString(aux)
+		}
+		gen.aux = nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "package %s\n\n// generated by parser.Generate, do not edit\n\n", pkg)
+	var imports []string
+	if len(gen.classes) > 0 {
+		imports = append(imports, "\"regexp\"")
+	}
+	if gen.folded {
+		imports = append(imports, "\"strings\"")
+	}
+	if !inPackage {
+		if len(imports) > 0 {
+			imports = append(imports, "")
+		}
+		imports = append(imports, "\"github.com/fuwjax/gopase/parser\"")
+	}
+	if len(imports) > 0 {
+		fmt.Fprintf(&sb, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+	fmt.Fprintf(&sb, "func %sImperative() *%sGrammar {\ngrammar, err := %sNewGrammar().\n", name, gen.prefix, gen.prefix)
+	for rule, r := range grammar.Rules() {
+		expr := fmt.Sprintf("%sGen(%sBody, %s)", gen.prefix, gen.function(rule), gen.source(r.expr))
+		if r.description == "" {
+			fmt.Fprintf(&sb, "AddRule(%q, %s).\n", rule, expr)
+		} else {
+			fmt.Fprintf(&sb, "Add(%sNewRule(%q, %s).Describe(%q)).\n", gen.prefix, rule, expr, r.description)
+		}
+	}
+	sb.WriteString("Compile()\nif err != nil {\npanic(err)\n}\nreturn grammar\n}\n")
+	if len(gen.classes) > 0 {
+		sb.WriteString("\nvar (\n")
+		for i, class := range gen.classes {
+			fmt.Fprintf(&sb, "%s%d = regexp.MustCompile(%q)\n", gen.vars, i, class)
+		}
+		sb.WriteString(")\n")
+	}
+	sb.WriteString(functions.String())
+	return format.Source([]byte(sb.String()))
+}
+
+type generator struct {
+	prefix  string
+	name    string
+	vars    string
+	grammar *Grammar
+	ids     map[string]int
+	rule    string
+	classes []string
+	folded  bool
+	aux     []string
+	count   int
+}
+
+/*
+Returns the name of the function generated for the rule.
+*/
+func (g *generator) function(rule string) string {
+	return g.name + strings.ReplaceAll(rule, ".", "_")
+}
+
+/*
+Returns a ParseFunc running the statements of body.
+*/
+func (g *generator) parseFunc(name, body string) string {
+	return fmt.Sprintf("\nfunc %s(c *%sParseContext) (*%sParseResult, error) {\nvar res *%sParseResult\nvar err error\n%sif err != nil {\nreturn nil, err\n}\nreturn res, nil\n}\n",
+		name, g.prefix, g.prefix, g.prefix, body)
+}
+
+/*
+Returns the number for the next set of variables and labels.
+*/
+func (g *generator) next() int {
+	g.count++
+	return g.count
+}
+
+/*
+Returns the name of a ParseFunc for expr, generated alongside the rule.
+*/
+func (g *generator) auxiliary(expr Expr) (string, error) {
+	body, err := g.code(expr)
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s_%d", g.function(g.rule), g.next())
+	g.aux = append(g.aux, g.parseFunc(name, body))
+	return name, nil
+}
+
+/*
+Returns the variable holding the compiled character class.
+*/
+func (g *generator) class(pattern string) string {
+	i := slices.Index(g.classes, pattern)
+	if i < 0 {
+		i = len(g.classes)
+		g.classes = append(g.classes, pattern)
+	}
+	return fmt.Sprintf("%s%d", g.vars, i)
+}
+
+/*
+Returns Go statements parsing expr, leaving its result in res and its error in
+err. Each is a single statement or block, so it never declares a variable a
+goto could skip.
+*/
+func (g *generator) code(expr Expr) (string, error) {
+	var sb strings.Builder
+	p := g.prefix
+	switch x := expr.(type) {
+	case *Reference:
+		if len(x.args) > 0 {
+			return "", fmt.Errorf("arguments to %s are not supported", x.name)
+		}
+		if g.grammar.rules[x.name] == nil {
+			fmt.Fprintf(&sb, "res, err = c.Apply(%d, nil)\n", g.ids[x.name])
+		} else {
+			fmt.Fprintf(&sb, "res, err = %s(c)\n", g.function(x.name))
+		}
+	case *Literal:
+		graphemes := graphemesOf(x.literal)
+		var conditions []string
+		for _, grapheme := range graphemes {
+			conditions = append(conditions, fmt.Sprintf("c.Token() == %q && c.Next() == nil", grapheme))
+		}
+		switch len(graphemes) {
+		case 0:
+			fmt.Fprintf(&sb, "res, err = %sNewResult(\"\", \"\"), nil\n", p)
+		case 1:
+			fmt.Fprintf(&sb, "if %s {\nres, err = %sNewResult(\"\", %q), nil\n} else {\nres, err = nil, c.Error(%q)\n}\n", conditions[0], p, x.literal, quote(x.literal))
+		default:
+			fmt.Fprintf(&sb, "if mark := c.Mark(); %s {\nres, err = %sNewResult(\"\", %q), nil\n} else {\nc.Reset(mark)\nres, err = nil, c.Error(%q)\n}\n", strings.Join(conditions, " && "), p, x.literal, quote(x.literal))
+		}
+	case *FoldedLiteral:
+		graphemes := graphemesOf(x.literal)
+		if len(graphemes) == 0 {
+			fmt.Fprintf(&sb, "res, err = %sNewResult(\"\", \"\"), nil\n", p)
+			break
+		}
+		g.folded = true
+		var conditions []string
+		for _, grapheme := range graphemes {
+			conditions = append(conditions, fmt.Sprintf("strings.EqualFold(c.Token(), %q) && c.Next() == nil", grapheme))
+		}
+		fmt.Fprintf(&sb, "if mark := c.Mark(); %s {\nres, err = %sNewResult(\"\", c.Substring(mark)), nil\n} else {\nc.Reset(mark)\nres, err = nil, c.Error(%q)\n}\n", strings.Join(conditions, " && "), p, quote(x.literal)+"i")
+	case *CharClass:
+		pattern := x.regex.String()
+		fmt.Fprintf(&sb, "if token := c.Token(); %s.MatchString(token) {\nres, err = %sNewResult(\"\", token), c.Next()\n} else {\nres, err = nil, c.Error(%q)\n}\n", g.class(pattern), p, pattern)
+	case *Any:
+		fmt.Fprintf(&sb, "res, err = %sNewResult(\"\", c.Token()), c.Next()\n", p)
+	case *BackReference:
+		fmt.Fprintf(&sb, "res, err = c.BackRef(%q)\n", x.name)
+	case *Recovery:
+		inner, err := g.auxiliary(x.expr)
+		if err != nil {
+			return "", err
+		}
+		sync, err := g.auxiliary(x.sync)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "res, err = c.Recover(%s, %s)\n", inner, sync)
+	case *Sequence:
+		n := g.next()
+		cut := slices.ContainsFunc(x.exprs, func(child Expr) bool {
+			_, ok := child.(*Commit)
+			return ok
+		})
+		fmt.Fprintf(&sb, "{\nvar result%d *%sParseResult\n", n, p)
+		if cut {
+			fmt.Fprintf(&sb, "cut%d := false\n", n)
+		}
+		matched := false
+		for _, child := range x.exprs {
+			if _, ok := child.(*Commit); ok {
+				fmt.Fprintf(&sb, "c.Cut()\ncut%d = true\n", n)
+				continue
+			}
+			code, err := g.code(child)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&sb, "%sif err != nil {\ngoto end%d\n}\nresult%d = result%d.Chain(res)\n", code, n, n, n)
+			matched = true
+		}
+		if matched {
+			fmt.Fprintf(&sb, "end%d:\n", n)
+		} else {
+			sb.WriteString("err = nil\n")
+		}
+		if cut {
+			fmt.Fprintf(&sb, "if err != nil && cut%d {\nerr = c.Commit(err)\n}\n", n)
+		}
+		fmt.Fprintf(&sb, "res = result%d\n}\n", n)
+	case *Options:
+		n := g.next()
+		fmt.Fprintf(&sb, "{\nmark%d := c.Mark()\nvar failure%d error\n", n, n)
+		for _, child := range x.exprs {
+			code, err := g.code(child)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&sb, "%sif err == nil || %sIsHard(err) {\ngoto end%d\n}\nfailure%d = c.Backtrack(failure%d, err, mark%d)\n", code, p, n, n, n, n)
+		}
+		fmt.Fprintf(&sb, "res, err = nil, failure%d\nend%d:\n}\n", n, n)
+	case *Optional:
+		n := g.next()
+		code, err := g.code(x.expr)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "{\nmark%d := c.Mark()\n%sif err != nil && !%sIsHard(err) {\nc.Reset(mark%d)\nres, err = nil, nil\n}\n}\n", n, code, p, n)
+	case *Repeated:
+		n := g.next()
+		code, err := g.code(x.expr)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "{\nvar result%d *%sParseResult\nfor {\nmark%d := c.Mark()\n%sif %sIsHard(err) {\nbreak\n}\n", n, p, n, code, p)
+		fmt.Fprintf(&sb, "if err != nil || c.At(mark%d) {\nc.Reset(mark%d)\nerr = nil\nbreak\n}\nresult%d = result%d.Chain(res)\n}\nres = result%d\n}\n", n, n, n, n, n)
+	case *Required:
+		n := g.next()
+		code, err := g.code(x.expr)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "{\nvar result%d *%sParseResult\nfor count%d := 0; ; count%d++ {\nmark%d := c.Mark()\n%s", n, p, n, n, n, code)
+		fmt.Fprintf(&sb, "if err != nil && (count%d == 0 || %sIsHard(err)) {\nbreak\n}\n", n, p)
+		fmt.Fprintf(&sb, "if err != nil || count%d > 0 && c.At(mark%d) {\nc.Reset(mark%d)\nerr = nil\nbreak\n}\nresult%d = result%d.Chain(res)\n}\nres = result%d\n}\n", n, n, n, n, n, n)
+	case *Bounded:
+		if err := x.check(); err != nil {
+			return "", err
+		}
+		n := g.next()
+		code, err := g.code(x.expr)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString("{\n")
+		if x.min > 0 {
+			fmt.Fprintf(&sb, "start%d := c.Mark()\n", n)
+		}
+		fmt.Fprintf(&sb, "var result%d *%sParseResult\n", n, p)
+		switch {
+		case x.max == 0:
+			fmt.Fprintf(&sb, "err = nil\nfor count%d := 0; count%d < 0; count%d++ {\n", n, n, n)
+		case x.max > 0:
+			fmt.Fprintf(&sb, "for count%d := 0; count%d < %d; count%d++ {\n", n, n, x.max, n)
+		case x.min > 0:
+			fmt.Fprintf(&sb, "for count%d := 0; ; count%d++ {\n", n, n)
+		default:
+			sb.WriteString("for {\n")
+		}
+		fmt.Fprintf(&sb, "mark%d := c.Mark()\n%sif %sIsHard(err) {\nbreak\n}\n", n, code, p)
+		if x.min > 0 {
+			fmt.Fprintf(&sb, "if err != nil && count%d < %d {\nc.Reset(start%d)\nbreak\n}\n", n, x.min, n)
+		}
+		fmt.Fprintf(&sb, "if err != nil {\nc.Reset(mark%d)\nerr = nil\nbreak\n}\nresult%d = result%d.Chain(res)\nif c.At(mark%d) {\nbreak\n}\n}\nres = result%d\n}\n", n, n, n, n, n)
+	case *PositiveLookahead:
+		n := g.next()
+		code, err := g.code(x.expr)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "{\nmark%d := c.Mark()\nc.Quiet(true)\n%sc.Quiet(false)\nc.Reset(mark%d)\nres = nil\n}\n", n, code, n)
+	case *NegativeLookahead:
+		n := g.next()
+		code, err := g.code(x.expr)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "{\nmark%d := c.Mark()\nc.Quiet(true)\n%sc.Quiet(false)\nc.Reset(mark%d)\n", n, code, n)
+		fmt.Fprintf(&sb, "if err == nil {\nerr = c.Error(%q)\n} else if !%sIsHard(err) {\nerr = nil\n}\nres = nil\n}\n", x.expected(), p)
+	case *Labeled:
+		code, err := g.code(x.expr)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "%sif err == nil {\nres = res.Label(%q)\n}\n", code, x.label)
+	case *Capturing:
+		n := g.next()
+		code, err := g.code(x.expr)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "{\nmark%d := c.Mark()\n%sif err == nil {\nres = c.Captured(mark%d, res)\n}\n}\n", n, code, n)
+	case *Binding:
+		n := g.next()
+		code, err := g.code(x.expr)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "{\nmark%d := c.Mark()\n%sif err == nil {\nc.Bind(%q, mark%d)\n}\n}\n", n, code, x.name, n)
+	case *Commit:
+		return "", fmt.Errorf("%s outside a sequence is not supported", expr)
+	default:
+		return "", fmt.Errorf("%s is not supported", expr)
+	}
+	return sb.String(), nil
+}
+
+/*
+Returns a Go expression constructing expr, which code has already accepted.
+*/
+func (g *generator) source(expr Expr) string {
+	p := g.prefix
+	sources := func(exprs ...Expr) string {
+		return strings.Join(funki.Apply(exprs, g.source), ", ")
+	}
+	switch x := expr.(type) {
+	case *Sequence:
+		return fmt.Sprintf("%sSeq(%s)", p, sources(x.exprs...))
+	case *Options:
+		return fmt.Sprintf("%sAlt(%s)", p, sources(x.exprs...))
+	case *Optional:
+		return fmt.Sprintf("%sOpt(%s)", p, g.source(x.expr))
+	case *Repeated:
+		return fmt.Sprintf("%sRep(%s)", p, g.source(x.expr))
+	case *Required:
+		return fmt.Sprintf("%sReq(%s)", p, g.source(x.expr))
+	case *Bounded:
+		return fmt.Sprintf("%sBound(%s, %d, %d)", p, g.source(x.expr), x.min, x.max)
+	case *Recovery:
+		return fmt.Sprintf("%sRecover(%s)", p, sources(x.expr, x.sync))
+	case *PositiveLookahead:
+		return fmt.Sprintf("%sSee(%s)", p, g.source(x.expr))
+	case *NegativeLookahead:
+		return fmt.Sprintf("%sNot(%s)", p, g.source(x.expr))
+	case *Labeled:
+		return fmt.Sprintf("%sLabel(%q, %s)", p, x.label, g.source(x.expr))
+	case *Capturing:
+		return fmt.Sprintf("%sCapture(%s)", p, g.source(x.expr))
+	case *Binding:
+		return fmt.Sprintf("%sBind(%q, %s)", p, x.name, g.source(x.expr))
+	case *Reference:
+		return fmt.Sprintf("%sRef(%q)", p, x.name)
+	case *Literal:
+		return fmt.Sprintf("%sLit(%q)", p, x.literal)
+	case *FoldedLiteral:
+		return fmt.Sprintf("%sLitI(%q)", p, x.literal)
+	case *CharClass:
+		return fmt.Sprintf("%sCls(%q)", p, x.regex.String())
+	case *Any:
+		return p + "Dot()"
+	case *Commit:
+		return p + "Cut()"
+	case *BackReference:
+		return fmt.Sprintf("%sBackRef(%q)", p, x.name)
+	}
+	return "nil"
+}
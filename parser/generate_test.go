@@ -0,0 +1,83 @@
+package parser_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+func TestGenerateSelfHosting(t *testing.T) {
+	grammar := when.YouErr(os.ReadFile("../sample/peg.peg")).ExpectSuccess(t)
+	rules := when.YouErr(parser.Bootstrap(string(grammar))).ExpectSuccess(t)
+	contents := when.YouErr(os.ReadFile("peg_imperative.go")).ExpectSuccess(t)
+	params := map[string]any{"package": "parser", "name": "Peg", "inPackage": true}
+	when.YouErr(parser.Generate(rules, params)).Expect(t, contents)
+	when.You(parser.PegImperative().Rule("Path").String()).Expect(t, `Rule("Path", Gen(Alt(Ref("SingleLit"),Ref("DoubleLit"))))`)
+	when.You(parser.PegImperative().Validate()).Expect(t, parser.Problems(nil))
+}
+
+func TestGenerateIdentical(t *testing.T) {
+	peg := when.YouErr(os.ReadFile("../sample/peg.peg")).ExpectSuccess(t)
+	funki := when.YouErr(os.ReadFile("../funki/funki.peg")).ExpectSuccess(t)
+	inputs := []string{string(peg), string(funki), structGrammar,
+		"S = 'a' / ", "S = [a-", "S = 'a\nT = 'b'", "%import", "S <A, B> = A{2,} $x(B) $x", "S = ^ 'x'~>. (l:'y')?i"}
+	for _, input := range inputs {
+		expected, expectedErr := parser.Parse("Grammar", parser.PegGrammar(), parser.PegHandler, input)
+		actual, actualErr := parser.Parse("Grammar", parser.PegImperative(), parser.PegHandler, input)
+		if expectedErr != nil {
+			when.You(actual).Expect(t, expected)
+			when.You(actualErr).ExpectMatch(t, func(t *testing.T, actual error) bool {
+				return when.AssertError(t, actual, expectedErr.Error())
+			})
+			continue
+		}
+		when.You(actualErr).Expect(t, nil)
+		when.You(actual.(*parser.Grammar).String()).Expect(t, expected.(*parser.Grammar).String())
+	}
+}
+
+const sinkGrammar = `
+S = Item (',' ^ Item)* !.
+Item = Pair / Word / Num / Heredoc / Folded / Bad
+Pair = key:Word '=' value:(Num / Word)
+Word = $([a-z]+)
+Num = [0-9]{1,3} &[^0-9]
+Heredoc = '<' $tag([A-Z]+) '>' (!$tag .)* $tag
+Folded "yes or no" = 'yes'i / &'n' 'no'
+Bad = '?' ~> ','
+`
+
+func TestGenerateSink(t *testing.T) {
+	rules := when.YouErr(parser.Bootstrap(sinkGrammar)).ExpectSuccess(t)
+	contents := when.YouErr(os.ReadFile("sink_test.go")).ExpectSuccess(t)
+	params := map[string]any{"package": "parser_test", "name": "Sink"}
+	when.YouErr(parser.Generate(rules, params)).Expect(t, contents)
+
+	inputs := []string{"a", "a=b,c=12,YeS,no", "<EOT>a,b<EO>EOT", "12,1234", "a,", "nope", "a=", "?,?x,a"}
+	for _, handler := range []parser.SpanHandler{parser.WrapSpanHandler(nil), parser.TreeHandler} {
+		for _, input := range inputs {
+			expected, expectedErr := parser.Parse("S", rules, handler, input)
+			actual, actualErr := parser.Parse("S", SinkImperative(), handler, input)
+			when.You(actual).Expect(t, expected)
+			when.You(actualErr).Expect(t, expectedErr)
+			expected, expectedErr = parser.Parse("S", rules, handler, input, parser.WithRecovery())
+			actual, actualErr = parser.Parse("S", SinkImperative(), handler, input, parser.WithRecovery())
+			when.You(actual).Expect(t, expected)
+			when.You(actualErr).Expect(t, expectedErr)
+		}
+	}
+}
+
+func TestGenerateUnsupported(t *testing.T) {
+	params := map[string]any{"package": "p", "name": "X"}
+	generate := func(grammar string) ([]byte, error) {
+		return parser.Generate(when.YouErr(parser.Bootstrap(grammar)).ExpectSuccess(t), params)
+	}
+	when.YouErr(generate("S = List<'a'>\nList<X> = X+")).ExpectError(t, "cannot generate parameterized rule List")
+	when.YouErr(generate("S = A\nA = 'a'\n%token A")).ExpectError(t, "cannot generate a grammar with %token, %ignore, %lexical or %skip")
+	when.YouErr(generate("S = 'a'\n%skip = ' '")).ExpectError(t, "cannot generate a grammar with %token, %ignore, %lexical or %skip")
+	when.YouErr(generate("S = 'a' / ^")).ExpectError(t, "cannot generate rule S: Cut() outside a sequence is not supported")
+	when.YouErr(generate("S = A")).ExpectError(t, "cannot generate rule S: no such rule: A")
+}
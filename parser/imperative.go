@@ -0,0 +1,179 @@
+package parser
+
+/*
+ParseFunc parses the body of a rule directly, as generated by Generate.
+*/
+type ParseFunc func(*ParseContext) (*ParseResult, error)
+
+type Generated struct {
+	parse  ParseFunc
+	source Expr
+}
+
+/*
+Parses with a function generated from the source expression. The source still
+describes the rule, so the grammar prints and validates as the original did.
+Generated functions call each other by memo id, so a generated rule only
+parses within the grammar it was generated for.
+*/
+func Gen(parse ParseFunc, source Expr) Expr {
+	return &Generated{parse, source}
+}
+
+func (x *Generated) Parse(context *ParseContext) (*ParseResult, error) {
+	return x.parse(context)
+}
+
+func (x *Generated) String() string {
+	return "Gen(" + x.source.String() + ")"
+}
+
+/*
+The methods below are the steps of generated parsers that need the state of
+the parse. Generated parsers match terminals themselves, and report rules to
+a Tracer, but not the terminals within them.
+*/
+
+/*
+Parses the rule with the memo id in a compiled grammar as a reference to it
+would, with body in place of the rule's expression. A nil body parses the
+rule's expression.
+*/
+func (c *ParseContext) Apply(id int, body ParseFunc) (*ParseResult, error) {
+	return c.grammar.refs[id].apply(c, body)
+}
+
+/*
+Releases the memoized results behind the current position, as Cut does. The
+sequence the cut is in commits its later failures with Commit.
+*/
+func (c *ParseContext) Cut() {
+	c.release()
+}
+
+/*
+Returns the error for an element of a sequence failing after a cut.
+*/
+func (c *ParseContext) Commit(err error) error {
+	return commit(err, c.Mark())
+}
+
+/*
+Returns to the mark after a failed alternative, merging its error into the
+failure of the alternatives so far.
+*/
+func (c *ParseContext) Backtrack(failure, err error, mark *ParsePosition) error {
+	pe, _ := failure.(*ParseError)
+	merged := pe.merge(asParseError(err, mark))
+	c.Reset(mark)
+	if c.profile != nil && c.rules != nil {
+		c.profile.rule(c.rules.name).Backtracks++
+	}
+	return merged
+}
+
+/*
+Stops recording failures while quiet, as inside a lookahead. Calls nest.
+*/
+func (c *ParseContext) Quiet(quiet bool) {
+	if quiet {
+		c.quiet++
+	} else {
+		c.quiet--
+	}
+}
+
+/*
+Returns the result of an expression matched from the mark as one Captured
+result.
+*/
+func (c *ParseContext) Captured(mark *ParsePosition, result *ParseResult) *ParseResult {
+	captured := Captured{c.Substring(mark), c.spanFrom(mark)}
+	return NewResult("", captured).Chain(recoveredOf(result))
+}
+
+/*
+Binds the text matched from the mark to the name.
+*/
+func (c *ParseContext) Bind(name string, mark *ParsePosition) {
+	c.fork(c.Mark().bind(name, c.Substring(mark)))
+}
+
+/*
+Matches the text last bound to the name, like BackRef.
+*/
+func (c *ParseContext) BackRef(name string) (*ParseResult, error) {
+	mark := c.Mark()
+	text, ok := mark.bound(name)
+	if !ok {
+		return nil, c.fail(mark, "$"+name)
+	}
+	if !c.match(text, sameText) {
+		return nil, c.fail(mark, quote(text))
+	}
+	return NewResult("", text), nil
+}
+
+/*
+Matches expr, recovering at sync, like Recover.
+*/
+func (c *ParseContext) Recover(expr, sync ParseFunc) (*ParseResult, error) {
+	if !c.recovering {
+		return expr(c)
+	}
+	mark := c.Mark()
+	outer := c.farthest
+	c.farthest = farthest{}
+	result, err := expr(c)
+	failure := c.farthest.err
+	c.farthest = outer
+	if err == nil {
+		if failure != nil {
+			c.farthest.err = failure.merge(outer.err)
+		}
+		return result, nil
+	}
+	pe := asParseError(err, mark)
+	if pe.hard() && !pe.committed() {
+		return nil, err
+	}
+	if failure == nil {
+		failure = pe
+	}
+	c.Reset(mark)
+	c.quiet++
+	for {
+		skipped := c.Mark()
+		_, err := sync(c)
+		c.Reset(skipped)
+		if err == nil || c.Next() != nil {
+			break
+		}
+	}
+	c.quiet--
+	return NewResult(ErrorKey, &ErrorNode{failure, c.Substring(mark)}), nil
+}
+
+/*
+Returns this result as one labeled result: its single value, all of its values
+if it has more than one, or nil if it has none.
+*/
+func (r *ParseResult) Label(label string) *ParseResult {
+	var values []any
+	for name, value := range r.Results() {
+		if name != ErrorKey {
+			values = append(values, value)
+		}
+	}
+	return NewResult(label, labelValue(values)).Chain(recoveredOf(r))
+}
+
+func labelValue(values []any) any {
+	switch len(values) {
+	case 0:
+		return nil
+	case 1:
+		return values[0]
+	}
+	return values
+}
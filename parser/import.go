@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	importLock sync.Mutex
+	registry   = make(map[string]*Grammar)
+)
+
+/*
+Registers a grammar under a name, so other grammars can import it with
+%import name.
+*/
+func RegisterGrammar(name string, grammar *Grammar) {
+	importLock.Lock()
+	defer importLock.Unlock()
+	registry[name] = grammar
+}
+
+/*
+Returns the grammar registered under the name, or nil.
+*/
+func RegisteredGrammar(name string) *Grammar {
+	importLock.Lock()
+	defer importLock.Unlock()
+	return registry[name]
+}
+
+/*
+Reads and bootstraps the grammar at the path, failing on import cycles.
+Relative paths imported by the grammar are resolved against its directory.
+*/
+func LoadGrammar(path string) (*Grammar, error) {
+	return loadGrammar(path, nil)
+}
+
+/*
+Loads the grammar at the path, imported through the chain of files being
+loaded.
+*/
+func loadGrammar(path string, loading []string) (*Grammar, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(loading, abs) {
+		return nil, fmt.Errorf("import cycle through %s", path)
+	}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	handler := pegHandler{filepath.Dir(abs), append(slices.Clip(loading), abs)}
+	return BootstrapParser[*Grammar]("Grammar", PegImperative(), WrapHandler(handler))(string(source))
+}
+
+/*
+Adds every rule of the other grammar to this one. A rule already defined here
+with a different expression is a conflict; conflicts are left unchanged and
+reported together.
+*/
+func (g *Grammar) Merge(other *Grammar) error {
+	return g.merge(other, false)
+}
+
+/*
+Adds every rule of the other grammar to this one under the namespace, so
+String becomes json.String. References between the imported rules are
+qualified the same way, which lets this grammar override json.WS for all of
+them by adding its own rule of that name.
+*/
+func (g *Grammar) Import(namespace string, other *Grammar) error {
+	qualified := NewGrammar()
+	for _, rule := range other.Rules() {
+		qualified.Add(rule.qualify(namespace, other))
+	}
+	return g.merge(qualified, true)
+}
+
+func (g *Grammar) merge(other *Grammar, imported bool) error {
+	var conflicts Problems
+	for name, rule := range other.Rules() {
+		existing := g.rules[name]
+		switch {
+		case existing == nil:
+			g.Add(rule)
+			if imported {
+				g.imported[name] = true
+			}
+		case existing.String() != rule.String():
+			conflicts = append(conflicts, Problem{DuplicateRule, name, "conflicts with an existing definition"})
+		}
+	}
+	return conflicts.Err()
+}
+
+/*
+Returns a copy of the rule from the grammar with its name and references
+prefixed by the namespace. References to the rule's own parameters, and to
+built-in rules the grammar does not define itself, are left alone.
+*/
+func (r *Rule) qualify(namespace string, grammar *Grammar) *Rule {
+	prefix := namespace + "."
+	expr := transform(r.expr, func(expr Expr) Expr {
+		ref, ok := expr.(*Reference)
+		if !ok || len(ref.args) == 0 && slices.Contains(r.params, ref.name) {
+			return expr
+		}
+		if builtins[ref.name] != nil && grammar.rules[ref.name] == nil {
+			return expr
+		}
+		return &Reference{prefix + ref.name, ref.args, nil}
+	})
+	return &Rule{prefix + r.name, expr, r.description, r.params, r.lexical}
+}
+
+/*
+An %import directive, resolved to the grammar it names.
+*/
+type grammarImport struct {
+	namespace string
+	grammar   *Grammar
+}
+
+/*
+Resolves an %import of a registered name or a file path, from the chain of
+files being loaded. The namespace defaults to the name, or to the file name
+without its extension.
+*/
+func resolveImport(name, path, alias string, loading []string) (*grammarImport, error) {
+	var grammar *Grammar
+	if path != "" {
+		loaded, err := loadGrammar(path, loading)
+		if err != nil {
+			return nil, err
+		}
+		grammar = loaded
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	} else if grammar = RegisteredGrammar(name); grammar == nil {
+		return nil, fmt.Errorf("no such grammar: %s", name)
+	}
+	if alias != "" {
+		name = alias
+	}
+	return &grammarImport{name, grammar}, nil
+}
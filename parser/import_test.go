@@ -0,0 +1,135 @@
+package parser_test
+
+import (
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+func TestGrammarMerge(t *testing.T) {
+	grammar := parser.NewGrammar().AddRule("S", parser.Ref("WS")).AddRule("WS", parser.Rep(parser.Lit(" ")))
+	other := parser.NewGrammar().AddRule("WS", parser.Rep(parser.Lit(" "))).AddRule("EOF", parser.Not(parser.Dot()))
+	when.YouErr(grammar, grammar.Merge(other)).ExpectSuccess(t)
+	when.You(grammar.Rule("EOF")).Expect(t, parser.NewRule("EOF", parser.Not(parser.Dot())))
+
+	conflict := parser.NewGrammar().AddRule("WS", parser.Rep(parser.Cls("[ \t]")))
+	when.YouErr(grammar, grammar.Merge(conflict)).ExpectError(t, "duplicate rule in WS: conflicts with an existing definition")
+	when.You(grammar.Rule("WS")).Expect(t, parser.NewRule("WS", parser.Rep(parser.Lit(" "))))
+}
+
+func TestGrammarImport(t *testing.T) {
+	other := parser.NewGrammar().AddRule("List", parser.Seq(parser.Ref("Item"), parser.Rep(parser.Seq(parser.Lit(","), parser.Ref("Item"))))).AddRule("Item", parser.Cls("[a-z]"))
+	grammar := parser.NewGrammar().AddRule("S", parser.Ref("csv.List"))
+	when.YouErr(grammar, grammar.Import("csv", other)).ExpectSuccess(t)
+	when.You(grammar.Rule("csv.List")).Expect(t, parser.NewRule("csv.List", parser.Seq(parser.Ref("csv.Item"), parser.Rep(parser.Seq(parser.Lit(","), parser.Ref("csv.Item"))))))
+	when.You(grammar.Validate()).Expect(t, parser.Problems(nil))
+
+	grammar.AddRule("csv.Item", parser.Cls("[0-9]"))
+	when.You(grammar.Validate()).Expect(t, parser.Problems(nil))
+	parse := parser.BootstrapParser[string]("S", grammar, parser.WrapHandler(nil))
+	when.YouErr(parse("1,2")).Expect(t, "1,2")
+}
+
+func TestImportRegistered(t *testing.T) {
+	parser.RegisterGrammar("common", parser.NewGrammar().AddRule("WS", parser.Rep(parser.Cls("[ \t]"))).AddRule("Word", parser.Req(parser.Cls("[a-z]"))))
+	parse := parser.NewParser[string]("S", `
+%import common
+%import common as c
+S = common.Word common.WS c.Word
+common.Word = [A-Z]+
+`, nil)
+	when.YouErr(parse("HI there")).Expect(t, "HI there")
+
+	missing := parser.NewParser[string]("S", "%import nothing\nS = 'a'", nil)
+	when.YouErr(missing("a")).ExpectError(t, "no such grammar: nothing\nwhile in Import\nwhile in Line\nwhile in Grammar")
+}
+
+func TestImportPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "num.peg")
+	when.YouErr(0, os.WriteFile(path, []byte("Number = '-'? Digit+\nDigit = [0-9]"), 0o644)).ExpectSuccess(t)
+
+	parse := parser.NewParser[string]("Pair", `%import "`+filepath.ToSlash(path)+`"
+%import '`+filepath.ToSlash(path)+`' as n
+Pair = num.Number ',' n.Number
+`, nil)
+	when.YouErr(parse("-12,3")).Expect(t, "-12,3")
+
+	nested := filepath.Join(dir, "nested")
+	when.YouErr(0, os.Mkdir(nested, 0o755)).ExpectSuccess(t)
+	when.YouErr(0, os.WriteFile(filepath.Join(nested, "word.peg"), []byte("Word = [a-z]+"), 0o644)).ExpectSuccess(t)
+	when.YouErr(0, os.WriteFile(filepath.Join(nested, "pair.peg"), []byte(`%import "word.peg"
+Pair = word.Word '=' word.Word`), 0o644)).ExpectSuccess(t)
+	pair := when.YouErr(parser.LoadGrammar(filepath.Join(nested, "pair.peg"))).ExpectSuccess(t)
+	when.YouErr(parser.Parse("Pair", pair, parser.WrapHandler(nil), "a=b")).Expect(t, "a=b")
+
+	cycle := filepath.Join(dir, "cycle.peg")
+	when.YouErr(0, os.WriteFile(cycle, []byte(`%import "`+filepath.ToSlash(cycle)+`" as again`), 0o644)).ExpectSuccess(t)
+	when.YouErr(parser.LoadGrammar(cycle)).ExpectError(t, "import cycle through "+filepath.ToSlash(cycle)+"\nwhile in Import\nwhile in Line\nwhile in Grammar")
+}
+
+func TestImportConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	when.YouErr(0, os.WriteFile(filepath.Join(dir, "word.peg"), []byte("Word = [a-z]+"), 0o644)).ExpectSuccess(t)
+	path := filepath.Join(dir, "pair.peg")
+	when.YouErr(0, os.WriteFile(path, []byte(`%import "word.peg"
+Pair = word.Word '=' word.Word`), 0o644)).ExpectSuccess(t)
+	errs := make([]error, 8)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = parser.LoadGrammar(path)
+		}()
+	}
+	wg.Wait()
+	when.You(errs).Expect(t, make([]error, 8))
+}
+
+func TestImportIndent(t *testing.T) {
+	parser.RegisterGrammar("blk", when.YouErr(parser.Bootstrap(`
+Block = Name (EOL SAMEDENT Name)*
+Name = [a-z]+ (':' EOL INDENT Block DEDENT)?
+EOL = '\n'
+`)).ExpectSuccess(t))
+	parse := parser.NewParser[string]("S", "%import blk\nS = blk.Block !.", nil)
+	when.YouErr(parse("a:\n  b\n  c\nd")).Expect(t, "a:\n  b\n  c\nd")
+
+	grammar := parser.NewGrammar()
+	when.YouErr(grammar, grammar.Import("own", parser.NewGrammar().AddRule("S", parser.Ref("INDENT")).AddRule("INDENT", parser.Lit(">")))).ExpectSuccess(t)
+	when.You(grammar.Rule("own.S")).Expect(t, parser.NewRule("own.S", parser.Ref("own.INDENT")))
+}
+
+type wordHandler struct{}
+
+func (h wordHandler) Word(results iter.Seq2[string, any]) (any, error) {
+	var sb strings.Builder
+	for _, result := range results {
+		sb.WriteString(result.(string))
+	}
+	return strings.ToUpper(sb.String()), nil
+}
+
+func TestImportHandler(t *testing.T) {
+	parser.RegisterGrammar("words", parser.NewGrammar().AddRule("Word", parser.Req(parser.Cls("[a-z]"))))
+	parse := parser.NewParser[string]("S", "%import words\nS = words.Word ' ' words.Word", wordHandler{})
+	when.YouErr(parse("hi there")).Expect(t, "HI THERE")
+}
+
+func TestImportParameterized(t *testing.T) {
+	other := parser.NewGrammar().AddRule("List", parser.Seq(parser.Ref("Item"), parser.Rep(parser.Seq(parser.Ref("Sep"), parser.Ref("Item")))), "Item", "Sep")
+	grammar := parser.NewGrammar()
+	when.YouErr(grammar, grammar.Import("util", other)).ExpectSuccess(t)
+	when.You(grammar.Rule("util.List")).Expect(t, parser.NewRule("util.List", parser.Seq(parser.Ref("Item"), parser.Rep(parser.Seq(parser.Ref("Sep"), parser.Ref("Item")))), "Item", "Sep"))
+
+	grammar.AddRule("S", parser.Ref("util.List", parser.Cls("[a-z]"), parser.Lit(",")))
+	parse := parser.BootstrapParser[string]("S", grammar, parser.WrapHandler(nil))
+	when.YouErr(parse("a,b")).Expect(t, "a,b")
+}
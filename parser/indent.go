@@ -0,0 +1,124 @@
+package parser
+
+import "fmt"
+
+/*
+The rules every grammar can reference without defining them. A grammar rule of
+the same name takes precedence.
+*/
+var builtins = map[string]*Rule{
+	"INDENT":   NewRule("INDENT", Indent()),
+	"SAMEDENT": NewRule("SAMEDENT", Samedent()),
+	"DEDENT":   NewRule("DEDENT", Dedent()),
+}
+
+type Indentation struct {
+	delta int
+}
+
+/*
+Skips spaces and tabs and matches if the column reached is deeper than the
+current indentation, which becomes that column.
+*/
+func Indent() Expr {
+	return &Indentation{1}
+}
+
+/*
+Skips spaces and tabs and matches if the column reached is the current
+indentation.
+*/
+func Samedent() Expr {
+	return &Indentation{0}
+}
+
+/*
+Matches nothing and returns to the indentation before the last Indent. It
+fails if nothing is indented, or if the next line, past blank lines, starts at
+a column that is not an enclosing indentation.
+*/
+func Dedent() Expr {
+	return &Indentation{-1}
+}
+
+func (x *Indentation) Parse(context *ParseContext) (*ParseResult, error) {
+	mark := context.Mark()
+	if x.delta < 0 {
+		if mark.indents == nil {
+			return nil, context.trace(x, mark, context.fail(mark, "dedent"))
+		}
+		crossed, err := skipLines(context)
+		if err != nil {
+			return nil, err
+		}
+		at := context.Mark()
+		context.Reset(mark)
+		if (crossed || mark.Column() == 1) && !at.grapheme.IsEof() && !encloses(mark.indents.next, at.Column()) {
+			return nil, context.trace(x, mark, context.fail(at, "indentation at an enclosing column"))
+		}
+		context.fork(mark.fork(mark.bindings, mark.indents.next))
+		return nil, context.trace(x, mark, nil)
+	}
+	for context.Token() == " " || context.Token() == "\t" {
+		if err := context.Next(); err != nil {
+			return nil, err
+		}
+	}
+	at := context.Mark()
+	column := at.Column()
+	switch {
+	case x.delta > 0 && column > mark.indentation():
+		context.fork(at.fork(at.bindings, &indent{column, at.indents}))
+	case x.delta == 0 && column == mark.indentation():
+	case x.delta > 0:
+		context.Reset(mark)
+		return nil, context.trace(x, mark, context.fail(at, fmt.Sprintf("indentation past column %d", mark.indentation())))
+	default:
+		context.Reset(mark)
+		return nil, context.trace(x, mark, context.fail(at, fmt.Sprintf("indentation at column %d", mark.indentation())))
+	}
+	return NewResult("", context.Substring(mark)), context.trace(x, mark, nil)
+}
+
+/*
+Moves past spaces, tabs and line breaks, returning true if a line break was
+crossed.
+*/
+func skipLines(context *ParseContext) (bool, error) {
+	crossed := false
+	for {
+		switch context.Token() {
+		case " ", "\t":
+		case "\n", "\r", "\r\n":
+			crossed = true
+		default:
+			return crossed, nil
+		}
+		if err := context.Next(); err != nil {
+			return false, err
+		}
+	}
+}
+
+/*
+Returns true if the column is one of the indentations, including column 1
+with nothing indented.
+*/
+func encloses(indents *indent, column int) bool {
+	for i := indents; i != nil; i = i.next {
+		if i.column == column {
+			return true
+		}
+	}
+	return column == 1
+}
+
+func (x *Indentation) String() string {
+	switch {
+	case x.delta > 0:
+		return "Indent()"
+	case x.delta < 0:
+		return "Dedent()"
+	}
+	return "Samedent()"
+}
@@ -0,0 +1,73 @@
+package parser_test
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/fuwjax/gopase/funki"
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+const outline = `
+Doc = Block !.
+Block = Line (EOL SAMEDENT Line)*
+Line = Name (':' EOL INDENT Block DEDENT)?
+Name = [a-z]+
+EOL = '\n'
+`
+
+func outlineHandler() map[string]parser.Converter {
+	return map[string]parser.Converter{
+		"Doc": func(result iter.Seq2[string, any]) (any, error) {
+			_, block := funki.FirstOf(result, "Block")
+			return block, nil
+		},
+		"Block": func(result iter.Seq2[string, any]) (any, error) {
+			return slices.Collect(funki.Values(funki.FilterKeys(result, "Line"))), nil
+		},
+		"Line": func(result iter.Seq2[string, any]) (any, error) {
+			_, name := funki.FirstOf(result, "Name")
+			if _, block := funki.FirstOf(result, "Block"); block != nil {
+				return map[string]any{name.(string): block}, nil
+			}
+			return name, nil
+		},
+	}
+}
+
+func TestIndentation(t *testing.T) {
+	parse := parser.NewParser[any]("Doc", outline, outlineHandler())
+
+	when.YouDoErr("indent nested", testParser(parse, "a\nb:\n  c\n  d:\n    e\n  f\ng")).
+		Expect(t, []any{"a", map[string]any{"b": []any{"c", map[string]any{"d": []any{"e"}}, "f"}}, "g"})
+	when.YouDoErr("indent flat", testParser(parse, "a\nb")).Expect(t, []any{"a", "b"})
+	when.YouDoErr("indent missing", testParser(parse, "a:\nb")).ExpectError(t, "at 2:1 expected indentation past column 1\nwhile in INDENT\nwhile in Line\nwhile in Block\nwhile in Doc")
+	when.YouDoErr("indent uneven", testParser(parse, "a:\n  b\n c")).ExpectError(t, "at 3:2 expected one of indentation at column 3, indentation at an enclosing column\nwhile in Line\nwhile in Block\nwhile in Doc")
+	when.YouDoErr("dedent two levels", testParser(parse, "a:\n  b:\n    c\nd")).Expect(t, []any{map[string]any{"a": []any{map[string]any{"b": []any{"c"}}}}, "d"})
+	when.YouDoErr("dedent past blank lines", testParser(parse, "a:\n  b\n\n  \n    c")).ExpectError(t, "at 5:5 expected indentation at an enclosing column\nwhile in DEDENT\nwhile in Line\nwhile in Block\nwhile in Doc")
+}
+
+func TestIndentationDedentColumn(t *testing.T) {
+	parse := parser.NewParser[any]("S", "S = 'a' EOL INDENT 'b' EOL DEDENT 'c'\nEOL = '\\n'", nil)
+
+	when.YouDoErr("dedent to enclosing column", testParser(parse, "a\n  b\nc")).Expect(t, "a\n  b\nc")
+	when.YouDoErr("dedent to other column", testParser(parse, "a\n    b\n  c")).ExpectError(t, "at 3:3 expected indentation at an enclosing column\nwhile in DEDENT\nwhile in S")
+	when.YouDoErr("dedent at end of input", testParser(parser.NewParser[any]("S", "S = INDENT 'b' DEDENT", nil), "  b")).Expect(t, "  b")
+}
+
+func TestIndentationBacktracks(t *testing.T) {
+	parse := parser.NewParser[any]("S", "S = (INDENT 'x')? SAMEDENT 'y'\nT = DEDENT", nil)
+
+	when.YouDoErr("indent rolled back", testParser(parse, "y")).Expect(t, "y")
+	when.YouDoErr("indent not kept", testParser(parse, "  y")).ExpectError(t, "at 1:3 expected one of 'x', indentation at column 1\nwhile in S")
+
+	dedent := parser.NewParser[any]("T", "S = (INDENT 'x')? SAMEDENT 'y'\nT = DEDENT", nil)
+	when.YouDoErr("dedent without indent", testParser(dedent, "")).ExpectError(t, "at 1:0 expected dedent\nwhile in DEDENT\nwhile in T")
+}
+
+func TestIndentationOverride(t *testing.T) {
+	parse := parser.NewParser[any]("S", "S = 'a' INDENT 'b'\nINDENT = '>'", nil)
+	when.YouDoErr("indent overridden", testParser(parse, "a>b")).Expect(t, "a>b")
+}
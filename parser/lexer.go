@@ -0,0 +1,278 @@
+package parser
+
+import "slices"
+
+/*
+Whether a rule is parsed as part of the syntax, or run by the lexer phase to
+produce tokens.
+*/
+type lexical int
+
+const (
+	syntactic lexical = iota
+	exact
+	token
+	ignored
+)
+
+/*
+A %token, %ignore or %lexical directive, applied once the rules it names are added.
+*/
+type lexicalDirective struct {
+	kind  lexical
+	names []string
+}
+
+/*
+Token is a piece of input found by the lexer phase, either by a token rule or
+by a literal of a syntactic rule. Kind is the name of the token rule, and is
+empty for a literal. Value is the result of the token rule's handler.
+*/
+type Token struct {
+	Kind  string
+	Text  string
+	Value any
+	Span  Span
+}
+
+/*
+Marks the rules as token rules. Once a grammar has token rules, the input is
+split into tokens before parsing, and syntactic rules parse over the tokens: a
+reference to a token rule matches one token of that kind, and a literal matches
+one token with exactly its text.
+*/
+func (g *Grammar) Token(names ...string) *Grammar {
+	return g.mark(token, names)
+}
+
+/*
+Marks the rules as ignored token rules. The lexer matches them like any other
+token rule, then drops the tokens.
+*/
+func (g *Grammar) Ignore(names ...string) *Grammar {
+	return g.mark(ignored, names)
+}
+
+func (g *Grammar) mark(kind lexical, names []string) *Grammar {
+	for _, name := range names {
+		if rule := g.rules[name]; rule != nil {
+			rule.lexical = kind
+		}
+	}
+	return g
+}
+
+/*
+Returns the names of the token rules, in order.
+*/
+func (g *Grammar) Tokens() []string {
+	return g.lexicalNames(token)
+}
+
+/*
+Returns the names of the ignored token rules, in order.
+*/
+func (g *Grammar) Ignored() []string {
+	return g.lexicalNames(ignored)
+}
+
+func (g *Grammar) lexicalNames(kind lexical) []string {
+	var names []string
+	for name, rule := range g.Rules() {
+		if rule.lexical == kind {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+/*
+Returns true if the grammar has a lexer phase.
+*/
+func (g *Grammar) lexed() bool {
+	for _, rule := range g.rules {
+		if rule.lexical == token || rule.lexical == ignored {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Returns the literals of the syntactic rules, which the lexer matches as tokens
+of their own. Rules used by token rules are part of the lexer, so their
+literals are left out.
+*/
+func (g *Grammar) literals() []Expr {
+	lexer := make(map[string]bool)
+	for name, rule := range g.rules {
+		if rule.lexical == token || rule.lexical == ignored {
+			for used := range g.reachable(name) {
+				lexer[used] = true
+			}
+		}
+	}
+	var literals []Expr
+	for name, rule := range g.Rules() {
+		if lexer[name] {
+			continue
+		}
+		walk(rule.expr, func(expr Expr) {
+			switch x := expr.(type) {
+			case *Literal, *FoldedLiteral:
+				if !slices.ContainsFunc(literals, func(lit Expr) bool { return lit.String() == x.String() }) {
+					literals = append(literals, x)
+				}
+			}
+		})
+	}
+	return literals
+}
+
+/*
+Splits the input into tokens with the token rules of the grammar. At each
+position the longest match wins; a literal wins a tie with a token rule, and
+an earlier token rule wins a tie with a later one.
+*/
+func Lex(grammar *Grammar, handler any, input string) ([]Token, error) {
+	tokens, _, err := lex(grammar, WrapSpanHandler(handler), input)
+	return tokens, err
+}
+
+/*
+Lexes the input, also returning the end of input position.
+*/
+func lex(grammar *Grammar, handler SpanHandler, input string) ([]Token, *ParsePosition, error) {
+	context := newParseContext(input, grammar, handler)
+	context.quiet++
+	literals := grammar.literals()
+	names := slices.Concat(grammar.Tokens(), grammar.Ignored())
+	rules := make([]Expr, len(names))
+	for i, name := range names {
+		rules[i] = &Reference{name, nil, &resolved{grammar.rules[name], context.memoKey(name).id, name}}
+	}
+	var tokens []Token
+	for !context.current.grapheme.IsEof() {
+		start := context.Mark()
+		var best *Token
+		end := start
+		for _, literal := range literals {
+			context.Reset(start)
+			if _, err := literal.Parse(context); err == nil && context.current.offset > end.offset {
+				best, end = &Token{"", context.Substring(start), context.Substring(start), newSpan(start, context.Mark())}, context.Mark()
+			}
+		}
+		for i, name := range names {
+			context.Reset(start)
+			result, err := rules[i].Parse(context)
+			if IsHard(err) {
+				return nil, nil, err
+			}
+			if err == nil && context.current.offset > end.offset {
+				best, end = &Token{name, context.Substring(start), result.value, newSpan(start, context.Mark())}, context.Mark()
+			}
+		}
+		if best == nil {
+			return nil, nil, newParseError(start, nil, "a token")
+		}
+		context.Reset(end)
+		if grammar.rules[best.Kind] == nil || grammar.rules[best.Kind].lexical == token {
+			tokens = append(tokens, *best)
+		}
+	}
+	return tokens, context.Mark(), nil
+}
+
+/*
+The tokens of a lexed input, from which token positions are created.
+*/
+type tokenStream struct {
+	tokens []Token
+	end    *ParsePosition
+}
+
+/*
+Creates the position of the i-th token, or of the end of input after the last
+token. The grapheme of a token position holds the whole token text, with the
+line and column where the token starts.
+*/
+func (s *tokenStream) position(i int, bindings *binding, indents *indent) *ParsePosition {
+	if i == len(s.tokens) {
+		return &ParsePosition{&Grapheme{"", "", s.end.Line(), s.end.Column(), i, 0, 0}, nil, nil, nil, nil, s.end.offset, bindings, indents, s}
+	}
+	t := s.tokens[i]
+	return &ParsePosition{&Grapheme{t.Text, "", t.Span.StartLine, t.Span.StartColumn, i, 0, 0}, nil, nil, nil, nil, t.Span.StartOffset, bindings, indents, s}
+}
+
+/*
+Creates the ParseContext for a parse, running the lexer phase first if the
+grammar has token rules.
+*/
+func startParse(input string, grammar *Grammar, handler SpanHandler) (*ParseContext, error) {
+	context := newParseContext(input, grammar, handler)
+	if !grammar.lexed() {
+		return context, nil
+	}
+	tokens, end, err := lex(grammar, handler, input)
+	if err != nil {
+		return nil, err
+	}
+	start := (&tokenStream{tokens, end}).position(0, nil, nil)
+	context.current = start
+	context.released = start
+	return context, nil
+}
+
+/*
+Returns true if the parse is over tokens instead of graphemes.
+*/
+func (c *ParseContext) lexed() bool {
+	return c.current.stream != nil
+}
+
+/*
+Returns the span from the start to the current position. After a lexer phase
+the span ends where the last matched token ends, not where the next begins.
+*/
+func (c *ParseContext) spanFrom(start *ParsePosition) Span {
+	span := newSpan(start, c.current)
+	if c.lexed() && c.current.grapheme.Pos > start.grapheme.Pos {
+		last := c.current.stream.tokens[c.current.grapheme.Pos-1].Span
+		span.EndLine, span.EndColumn, span.EndOffset = last.EndLine, last.EndColumn, last.EndOffset
+	}
+	return span
+}
+
+/*
+Matches the text at the current position grapheme by grapheme, or as one whole
+token after a lexer phase. Does not move on a mismatch.
+*/
+func (c *ParseContext) match(text string, equal func(a, b string) bool) bool {
+	mark := c.current
+	if text != "" && c.lexed() {
+		if !equal(text, c.Token()) || c.Next() != nil {
+			c.Reset(mark)
+			return false
+		}
+		return true
+	}
+	for ch := range Graphemes(text) {
+		if !equal(ch.Token, c.Token()) || c.Next() != nil {
+			c.Reset(mark)
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Matches one token produced by the token rule, yielding the token's value.
+*/
+func (r *Rule) parseToken(context *ParseContext) (any, []*ErrorNode, error) {
+	mark := context.Mark()
+	if mark.grapheme.IsEof() || mark.stream.tokens[mark.grapheme.Pos].Kind != r.name {
+		return nil, nil, context.fail(mark, r.name)
+	}
+	value := mark.stream.tokens[mark.grapheme.Pos].Value
+	return value, nil, context.Next()
+}
@@ -0,0 +1,76 @@
+package parser_test
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/fuwjax/gopase/funki"
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+const lexedJson = `
+%token Number String
+%ignore WS
+Value = Object / Array / Number / String / 'true' / 'false' / 'null'
+Object = '{' (Member (',' Member)*)? '}'
+Member = String ':' Value
+Array = '[' (Value (',' Value)*)? ']'
+Number = '-'? [0-9]+ ('.' [0-9]+)?
+String = '"' [^"]* '"'
+WS = [ \t\r\n]+
+`
+
+func TestLexer(t *testing.T) {
+	grammar := when.YouErr(parser.Bootstrap(lexedJson)).ExpectSuccess(t)
+	when.You(grammar.Tokens()).Expect(t, []string{"Number", "String"})
+	when.You(grammar.Ignored()).Expect(t, []string{"WS"})
+	when.You(grammar.Validate()).Expect(t, parser.Problems(nil))
+
+	when.YouErr(parser.Lex(grammar, parser.WrapHandler(nil), `[1, "a b"]`)).Expect(t, []parser.Token{
+		{"", "[", "[", parser.Span{1, 1, 0, 1, 2, 1}},
+		{"Number", "1", "1", parser.Span{1, 2, 1, 1, 3, 2}},
+		{"", ",", ",", parser.Span{1, 3, 2, 1, 4, 3}},
+		{"String", `"a b"`, `"a b"`, parser.Span{1, 5, 4, 1, 10, 9}},
+		{"", "]", "]", parser.Span{1, 10, 9, 1, 11, 10}},
+	})
+	when.YouErr(parser.Lex(grammar, parser.WrapHandler(nil), "nullish")).ExpectError(t, "at 1:5 expected a token")
+}
+
+func TestLexerParse(t *testing.T) {
+	parse := parser.NewParser[string]("Value", lexedJson, nil)
+
+	when.YouErr(parse("{ \"a\" : [ 1, -2.5 ],\n  \"b\": null }")).Expect(t, `{"a":[1,-2.5],"b":null}`)
+	when.YouErr(parse("[1 2]")).ExpectError(t, "at 1:4 expected one of ',', ']'\nwhile in Array\nwhile in Value")
+	when.YouErr(parse(`{1: 2}`)).ExpectError(t, "at 1:2 expected one of String, '}'\nwhile in Object\nwhile in Value")
+}
+
+func TestLexerKeywords(t *testing.T) {
+	parse := parser.NewParser[string]("Stmt", `
+%token Name
+%ignore WS
+Stmt = 'let' Name '=' Name
+Name = [a-z]+
+WS = ' '+
+`, nil)
+
+	when.YouErr(parse("let letter = lets")).Expect(t, "letletter=lets")
+	when.YouErr(parse("let let = x")).ExpectError(t, "at 1:5 expected Name\nwhile in Stmt")
+}
+
+func TestLexerSpans(t *testing.T) {
+	grammar := when.YouErr(parser.Bootstrap(`
+%token ID
+%ignore WS
+S = ID $(ID)
+ID = [a-z]+
+WS = ' '+
+`)).ExpectSuccess(t)
+	node := when.YouErr(parser.Parse("S", grammar, parser.TreeHandler, "ab   cd   ")).ExpectSuccess(t).(*parser.Node)
+	when.You(node.Span.String()).Expect(t, "1:1..1:8")
+	spans := map[string]parser.SpanConverter{"S": func(results iter.Seq2[string, any], span parser.Span) (any, error) {
+		_, captured := funki.FirstOf(results, "")
+		return []parser.Span{span, captured.(parser.Captured).Span}, nil
+	}}
+	when.YouErr(parser.Parse("S", grammar, spans, "ab   cd   ")).Expect(t, []parser.Span{{1, 1, 0, 1, 8, 7}, {1, 6, 5, 1, 8, 7}})
+}
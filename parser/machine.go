@@ -0,0 +1,787 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+/*
+The instructions of the parsing machine. A position is an index into the
+graphemes of the input.
+*/
+type opcode int
+
+const (
+	opEnd        opcode = iota // stops with the value of the root rule
+	opLiteral                  // matches the graphemes of a literal
+	opFolded                   // matches the graphemes of a literal, ignoring case
+	opClass                    // matches one grapheme against a character class
+	opAny                      // matches any one grapheme
+	opChoice                   // saves the state to backtrack to arg
+	opCommit                   // drops the last choice and jumps to arg
+	opRepCommit                // jumps to arg if the last choice moved forward, updating it; else backtracks to it
+	opBackCommit               // backtracks to the last choice, dropping it, and jumps to arg
+	opNotFail                  // backtracks to the last choice, dropping it, then fails as a negative lookahead
+	opFail                     // fails
+	opJump                     // jumps to arg
+	opCall                     // calls the rule arg
+	opReturn                   // converts the results of the rule and returns
+	opSkip                     // calls the skip subroutine
+	opRet                      // returns from the skip subroutine
+	opQuiet                    // stops recording failures
+	opLoud                     // undoes opQuiet
+	opOpen                     // marks the position and results
+	opLabel                    // replaces the results since the mark with one labeled result
+	opCapture                  // replaces the results since the mark with a Captured result
+	opDrop                     // drops the results since the mark
+	opIfStill                  // jumps to arg if nothing was matched since the mark
+	opBarrier                  // commits failures from here on, for a cut
+	opPopBarrier               // drops the barrier of a cut
+)
+
+type instruction struct {
+	op      opcode
+	arg     int
+	text    string
+	literal []string
+	class   *regexp.Regexp
+}
+
+/*
+A rule of a Program. Pc is where its code starts, and entry where a parse
+starting from it does: skipping, then calling it, then ending.
+*/
+type programRule struct {
+	name, description string
+	pc, entry         int
+}
+
+/*
+Program is a grammar compiled for the parsing machine: a flat sequence of
+instructions run with a backtrack stack, instead of a tree of expressions
+parsed with a packrat cache. It accepts the handlers of Parse and gives the
+same results. A grammar the machine does not support is parsed with Parse
+instead.
+*/
+type Program struct {
+	code     []instruction
+	rules    []programRule
+	index    map[string]int
+	skip     int
+	fallback *Grammar
+}
+
+/*
+A grammar feature the parsing machine cannot run, since it memoizes nothing
+and keeps no state but the position.
+*/
+type unsupportedError struct {
+	feature string
+}
+
+func (e *unsupportedError) Error() string {
+	return "the parsing machine does not support " + e.feature
+}
+
+/*
+Compiles the grammar for the parsing machine. Grammars with left recursion,
+token rules, back-references or indentation are compiled for Parse instead,
+so a Program runs every grammar Parse does.
+*/
+func NewProgram(grammar *Grammar) (*Program, error) {
+	program, err := compileProgram(grammar)
+	var unsupported *unsupportedError
+	if errors.As(err, &unsupported) {
+		compiled, err := grammar.Compile()
+		if err != nil {
+			return nil, err
+		}
+		return &Program{nil, nil, nil, -1, compiled}, nil
+	}
+	return program, err
+}
+
+/*
+Returns true if the program runs on the parsing machine, false if it falls
+back to Parse.
+*/
+func (p *Program) Machine() bool {
+	return p.fallback == nil
+}
+
+func compileProgram(grammar *Grammar) (*Program, error) {
+	if grammar.lexed() {
+		return nil, &unsupportedError{"%token or %ignore"}
+	}
+	nullable := grammar.nullable()
+	for _, name := range grammar.ruleNames() {
+		if path := grammar.leftCycle(name, nullable); path != nil {
+			return nil, &unsupportedError{"left recursion: " + strings.Join(path, " -> ")}
+		}
+	}
+	c := &compiler{grammar, &Program{nil, nil, make(map[string]int), -1, nil}, grammar.exactRules(), nil, nil}
+	if grammar.skip != nil {
+		c.program.skip = len(c.program.code)
+		c.emit(instruction{op: opQuiet})
+		c.emit(instruction{op: opOpen})
+		if err := c.repeat(grammar.skip, true); err != nil {
+			return nil, fmt.Errorf("skip: %w", err)
+		}
+		c.emit(instruction{op: opDrop})
+		c.emit(instruction{op: opLoud})
+		c.emit(instruction{op: opRet})
+	}
+	for name, rule := range grammar.Rules() {
+		if len(rule.params) == 0 {
+			if _, err := c.ruleIndex(&Reference{name, nil, nil}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for len(c.pending) > 0 {
+		index, rule := c.pending[0].index, c.pending[0].rule
+		c.current = c.pending[0].from
+		c.pending = c.pending[1:]
+		c.program.rules[index].pc = len(c.program.code)
+		if err := c.compile(rule.expr, c.exact[rule.name]); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", c.program.rules[index].name, err)
+		}
+		c.emit(instruction{op: opReturn})
+	}
+	for index, rule := range c.program.rules {
+		c.program.rules[index].entry = len(c.program.code)
+		c.skip(c.exact[rule.name])
+		c.emit(instruction{op: opCall, arg: index})
+		c.emit(instruction{op: opEnd})
+	}
+	return c.program, nil
+}
+
+type pendingRule struct {
+	index int
+	rule  *Rule
+	from  *instantiation
+}
+
+/*
+Compiles a grammar into a program. Current is the instance of a parameterized
+rule being compiled, if any.
+*/
+type compiler struct {
+	grammar *Grammar
+	program *Program
+	exact   map[string]bool
+	pending []pendingRule
+	current *instantiation
+}
+
+func (c *compiler) emit(in instruction) int {
+	c.program.code = append(c.program.code, in)
+	return len(c.program.code) - 1
+}
+
+/*
+Points the jump of the instruction at the next instruction to be emitted.
+*/
+func (c *compiler) patch(at int) {
+	c.program.code[at].arg = len(c.program.code)
+}
+
+/*
+Returns the index of the rule a reference calls, queueing it to be compiled
+the first time. Parameterized rules are compiled once for each set of
+arguments, and fail if they instantiate themselves with ever longer arguments.
+*/
+func (c *compiler) ruleIndex(ref *Reference) (int, error) {
+	key := ref.key()
+	if index, ok := c.program.index[key]; ok {
+		return index, nil
+	}
+	rule := c.grammar.Rule(ref.name)
+	if rule == nil && builtins[ref.name] != nil {
+		return 0, &unsupportedError{ref.name}
+	}
+	if rule == nil {
+		return 0, fmt.Errorf("no such rule: %s", ref.name)
+	}
+	var from *instantiation
+	if len(ref.args) > 0 || len(rule.params) > 0 {
+		var err error
+		if from, err = c.current.instantiate(ref); err != nil {
+			return 0, err
+		}
+		if rule, err = rule.instantiate(ref.args); err != nil {
+			return 0, err
+		}
+	}
+	index := len(c.program.rules)
+	c.program.rules = append(c.program.rules, programRule{rule.name, rule.description, 0, 0})
+	c.program.index[key] = index
+	c.pending = append(c.pending, pendingRule{index, rule, from})
+	return index, nil
+}
+
+/*
+Skips before a terminal or a call, unless compiling an exact rule.
+*/
+func (c *compiler) skip(exact bool) {
+	if !exact && c.program.skip >= 0 {
+		c.emit(instruction{op: opSkip})
+	}
+}
+
+func (c *compiler) compile(expr Expr, exact bool) error {
+	switch x := expr.(type) {
+	case *Literal:
+		c.skip(exact)
+		c.emit(instruction{op: opLiteral, text: quote(x.literal), literal: graphemesOf(x.literal)})
+	case *FoldedLiteral:
+		c.skip(exact)
+		c.emit(instruction{op: opFolded, text: quote(x.literal) + "i", literal: graphemesOf(x.literal)})
+	case *CharClass:
+		c.skip(exact)
+		c.emit(instruction{op: opClass, text: x.regex.String(), class: x.regex})
+	case *Any:
+		c.skip(exact)
+		c.emit(instruction{op: opAny})
+	case *Reference:
+		c.skip(exact)
+		index, err := c.ruleIndex(x)
+		if err != nil {
+			return err
+		}
+		c.emit(instruction{op: opCall, arg: index})
+	case *Sequence:
+		cut := false
+		for _, child := range x.exprs {
+			if _, ok := child.(*Commit); ok {
+				if !cut {
+					c.emit(instruction{op: opBarrier})
+				}
+				cut = true
+				continue
+			}
+			if err := c.compile(child, exact); err != nil {
+				return err
+			}
+		}
+		if cut {
+			c.emit(instruction{op: opPopBarrier})
+		}
+	case *Options:
+		var commits []int
+		for _, child := range x.exprs[:len(x.exprs)-1] {
+			choice := c.emit(instruction{op: opChoice})
+			if err := c.compile(child, exact); err != nil {
+				return err
+			}
+			commits = append(commits, c.emit(instruction{op: opCommit}))
+			c.patch(choice)
+		}
+		if err := c.compile(x.exprs[len(x.exprs)-1], exact); err != nil {
+			return err
+		}
+		for _, commit := range commits {
+			c.patch(commit)
+		}
+	case *Optional:
+		choice := c.emit(instruction{op: opChoice})
+		if err := c.compile(x.expr, exact); err != nil {
+			return err
+		}
+		commit := c.emit(instruction{op: opCommit})
+		c.patch(choice)
+		c.patch(commit)
+	case *Repeated:
+		return c.repeat(x.expr, exact)
+	case *Required:
+		if err := c.compile(x.expr, exact); err != nil {
+			return err
+		}
+		return c.repeat(x.expr, exact)
+	case *Bounded:
+		return c.bound(x, exact)
+	case *Generated:
+		return c.compile(x.source, exact)
+	case *PositiveLookahead:
+		c.emit(instruction{op: opQuiet})
+		choice := c.emit(instruction{op: opChoice})
+		if err := c.compile(x.expr, exact); err != nil {
+			return err
+		}
+		back := c.emit(instruction{op: opBackCommit})
+		c.patch(choice)
+		c.emit(instruction{op: opLoud})
+		c.emit(instruction{op: opFail})
+		c.patch(back)
+		c.emit(instruction{op: opLoud})
+	case *NegativeLookahead:
+		c.emit(instruction{op: opQuiet})
+		choice := c.emit(instruction{op: opChoice})
+		if err := c.compile(x.expr, exact); err != nil {
+			return err
+		}
+		c.emit(instruction{op: opNotFail, text: x.expected()})
+		c.patch(choice)
+		c.emit(instruction{op: opLoud})
+	case *Labeled:
+		c.emit(instruction{op: opOpen})
+		if err := c.compile(x.expr, exact); err != nil {
+			return err
+		}
+		c.emit(instruction{op: opLabel, text: x.label})
+	case *Capturing:
+		c.skip(exact)
+		c.emit(instruction{op: opOpen})
+		if err := c.compile(x.expr, exact); err != nil {
+			return err
+		}
+		c.emit(instruction{op: opCapture})
+	case *Recovery:
+		return c.compile(x.expr, exact)
+	default:
+		return &unsupportedError{expr.String()}
+	}
+	return nil
+}
+
+/*
+Compiles expr repeated until it fails or matches nothing, like Rep.
+*/
+func (c *compiler) repeat(expr Expr, exact bool) error {
+	loop := c.emit(instruction{op: opChoice})
+	if err := c.compile(expr, exact); err != nil {
+		return err
+	}
+	c.emit(instruction{op: opRepCommit, arg: loop + 1})
+	c.patch(loop)
+	return nil
+}
+
+/*
+Compiles a Bounded expression unrolled: the required matches, then the
+optional ones, or a loop if there is no maximum. Like Bounded, it stops early
+once expr matches nothing.
+*/
+func (c *compiler) bound(x *Bounded, exact bool) error {
+	if err := x.check(); err != nil {
+		return err
+	}
+	var done []int
+	for range x.min {
+		c.emit(instruction{op: opOpen})
+		if err := c.compile(x.expr, exact); err != nil {
+			return err
+		}
+		done = append(done, c.emit(instruction{op: opIfStill}))
+	}
+	for count := x.min; x.max < 0 || count < x.max; count++ {
+		loop := c.emit(instruction{op: opChoice})
+		done = append(done, loop)
+		c.emit(instruction{op: opOpen})
+		if err := c.compile(x.expr, exact); err != nil {
+			return err
+		}
+		c.emit(instruction{op: opCommit, arg: len(c.program.code) + 1})
+		done = append(done, c.emit(instruction{op: opIfStill}))
+		if x.max < 0 {
+			c.emit(instruction{op: opJump, arg: loop})
+			break
+		}
+	}
+	for _, at := range done {
+		c.patch(at)
+	}
+	return nil
+}
+
+func graphemesOf(text string) []string {
+	var graphemes []string
+	for g := range Graphemes(text) {
+		graphemes = append(graphemes, g.Token)
+	}
+	return graphemes
+}
+
+/*
+Parses the input from the root rule, with the same results and errors as Parse.
+*/
+func (p *Program) Parse(root string, handler any, input string) (any, error) {
+	if p.fallback != nil {
+		return Parse(root, p.fallback, handler, input)
+	}
+	m := newMachine(p, WrapSpanHandler(handler), input)
+	index, ok := p.index[root]
+	if !ok {
+		return nil, &ParseError{m.lines[0], m.columns[0], 0, nil, nil, fmt.Errorf("no such rule: %s", root)}
+	}
+	return m.run(index)
+}
+
+type entryKind int
+
+const (
+	choiceEntry entryKind = iota
+	callEntry
+	skipEntry
+	barrierEntry
+)
+
+/*
+An entry of the backtrack stack. Choices and calls save the state to return
+to; a call also remembers its rule.
+*/
+type entry struct {
+	kind                   entryKind
+	pc, pos, values, marks int
+	quiet, rule            int
+}
+
+type machineMark struct {
+	pos, values int
+}
+
+type machineResult struct {
+	name  string
+	value any
+}
+
+/*
+The state of one run of a Program.
+*/
+type machine struct {
+	program    *Program
+	handler    SpanHandler
+	input      string
+	tokens     []string
+	offsets    []int
+	lines      []int
+	columns    []int
+	stack      []entry
+	values     []machineResult
+	marks      []machineMark
+	quiet      int
+	farthest   farthest
+	converters []SpanConverter
+	looked     []bool
+}
+
+func newMachine(program *Program, handler SpanHandler, input string) *machine {
+	m := &machine{program: program, handler: handler, input: input}
+	offset := 0
+	for g := NewGrapheme(input); ; g = g.Next() {
+		m.tokens = append(m.tokens, g.Token)
+		m.offsets = append(m.offsets, offset)
+		m.lines = append(m.lines, g.Line)
+		m.columns = append(m.columns, g.Column)
+		offset += len(g.Token)
+		if g.IsEof() {
+			break
+		}
+	}
+	m.converters = make([]SpanConverter, len(program.rules))
+	m.looked = make([]bool, len(program.rules))
+	return m
+}
+
+func (m *machine) run(root int) (any, error) {
+	pc, pos := m.program.rules[root].entry, 0
+	eof := len(m.tokens) - 1
+	for {
+		in := &m.program.code[pc]
+		failed := false
+		switch in.op {
+		case opEnd:
+			return m.values[0].value, nil
+		case opLiteral, opFolded:
+			start := pos
+			for _, g := range in.literal {
+				if pos == eof || !(m.tokens[pos] == g || in.op == opFolded && strings.EqualFold(m.tokens[pos], g)) {
+					failed = true
+					break
+				}
+				pos++
+			}
+			if failed {
+				m.record(start, in.text)
+				pos = start
+			} else if in.op == opFolded {
+				m.push("", m.input[m.offsets[start]:m.offsets[pos]])
+			} else {
+				m.push("", strings.Join(in.literal, ""))
+			}
+		case opClass:
+			if !in.class.MatchString(m.tokens[pos]) {
+				m.record(pos, in.text)
+				failed = true
+			} else if pos == eof {
+				m.record(pos, "anything")
+				failed = true
+			} else {
+				m.push("", m.tokens[pos])
+				pos++
+			}
+		case opAny:
+			if pos == eof {
+				m.record(pos, "anything")
+				failed = true
+			} else {
+				m.push("", m.tokens[pos])
+				pos++
+			}
+		case opChoice:
+			m.stack = append(m.stack, entry{choiceEntry, in.arg, pos, len(m.values), len(m.marks), m.quiet, 0})
+		case opCommit:
+			m.stack = m.stack[:len(m.stack)-1]
+			pc = in.arg
+			continue
+		case opRepCommit:
+			e := &m.stack[len(m.stack)-1]
+			if pos == e.pos {
+				m.values, m.marks = m.values[:e.values], m.marks[:e.marks]
+				pc = e.pc
+				m.stack = m.stack[:len(m.stack)-1]
+				continue
+			}
+			e.pos, e.values, e.marks = pos, len(m.values), len(m.marks)
+			pc = in.arg
+			continue
+		case opBackCommit:
+			e := m.stack[len(m.stack)-1]
+			m.stack = m.stack[:len(m.stack)-1]
+			pos, m.values, m.marks = e.pos, m.values[:e.values], m.marks[:e.marks]
+			pc = in.arg
+			continue
+		case opNotFail:
+			e := m.stack[len(m.stack)-1]
+			m.stack = m.stack[:len(m.stack)-1]
+			pos, m.values, m.marks = e.pos, m.values[:e.values], m.marks[:e.marks]
+			m.quiet = e.quiet - 1
+			m.record(pos, in.text)
+			failed = true
+		case opFail:
+			failed = true
+		case opJump:
+			pc = in.arg
+			continue
+		case opCall:
+			m.call(in.arg, pc+1, pos)
+			pc = m.program.rules[in.arg].pc
+			continue
+		case opReturn:
+			var err error
+			if pc, err = m.ret(pos); err != nil {
+				return nil, err
+			}
+			continue
+		case opSkip:
+			m.stack = append(m.stack, entry{skipEntry, pc + 1, pos, 0, 0, m.quiet, 0})
+			pc = m.program.skip
+			continue
+		case opRet:
+			pc = m.stack[len(m.stack)-1].pc
+			m.stack = m.stack[:len(m.stack)-1]
+			continue
+		case opQuiet:
+			m.quiet++
+		case opLoud:
+			m.quiet--
+		case opOpen:
+			m.marks = append(m.marks, machineMark{pos, len(m.values)})
+		case opLabel:
+			mark := m.popMark()
+			var values []any
+			for _, v := range m.values[mark.values:] {
+				values = append(values, v.value)
+			}
+			m.values = m.values[:mark.values]
+			m.push(in.text, labelValue(values))
+		case opCapture:
+			mark := m.popMark()
+			m.values = m.values[:mark.values]
+			m.push("", Captured{m.input[m.offsets[mark.pos]:m.offsets[pos]], m.span(mark.pos, pos)})
+		case opDrop:
+			m.values = m.values[:m.popMark().values]
+		case opIfStill:
+			if m.popMark().pos == pos {
+				pc = in.arg
+				continue
+			}
+		case opBarrier:
+			m.stack = append(m.stack, entry{barrierEntry, 0, pos, 0, 0, m.quiet, 0})
+		case opPopBarrier:
+			m.stack = m.stack[:len(m.stack)-1]
+		}
+		if !failed {
+			pc++
+			continue
+		}
+		var err error
+		if pc, pos, err = m.fail(pos); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (m *machine) push(name string, value any) {
+	m.values = append(m.values, machineResult{name, value})
+}
+
+func (m *machine) popMark() machineMark {
+	mark := m.marks[len(m.marks)-1]
+	m.marks = m.marks[:len(m.marks)-1]
+	return mark
+}
+
+func (m *machine) span(start, end int) Span {
+	return Span{m.lines[start], m.columns[start], m.offsets[start], m.lines[end], m.columns[end], m.offsets[end]}
+}
+
+/*
+Enters a rule. Failures inside a described rule are not recorded.
+*/
+func (m *machine) call(rule, pc, pos int) {
+	m.stack = append(m.stack, entry{callEntry, pc, pos, len(m.values), len(m.marks), m.quiet, rule})
+	if m.program.rules[rule].description != "" {
+		m.quiet++
+	}
+}
+
+/*
+Leaves a rule, replacing its results with the one converted by the handler.
+Returns where to continue, or the error of the converter.
+*/
+func (m *machine) ret(pos int) (int, error) {
+	e := m.stack[len(m.stack)-1]
+	m.stack = m.stack[:len(m.stack)-1]
+	rule := &m.program.rules[e.rule]
+	if !m.looked[e.rule] {
+		m.converters[e.rule] = m.handler(rule.name)
+		m.looked[e.rule] = true
+	}
+	results := slices.Clone(m.values[e.values:])
+	seq := func(yield func(string, any) bool) {
+		for _, result := range results {
+			if !yield(result.name, result.value) {
+				return
+			}
+		}
+	}
+	var value any = concat(seq)
+	if converter := m.converters[e.rule]; converter != nil {
+		var err error
+		if value, err = converter(seq, m.span(e.pos, pos)); err != nil {
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				pe = &ParseError{m.lines[e.pos], m.columns[e.pos], m.offsets[e.pos], nil, nil, err}
+			}
+			pe = pe.within(rule.name)
+			for _, name := range m.rules() {
+				pe = pe.within(name)
+			}
+			return 0, pe
+		}
+	}
+	m.values = m.values[:e.values]
+	m.push(rule.name, value)
+	m.quiet = e.quiet
+	return e.pc, nil
+}
+
+/*
+Backtracks to the last choice, returning where to continue from. Described
+rules failed on the way record their description. Backtracking into a cut, or
+past the root, ends the parse with the farthest failure.
+*/
+func (m *machine) fail(pos int) (int, int, error) {
+	for len(m.stack) > 0 {
+		e := m.stack[len(m.stack)-1]
+		m.stack = m.stack[:len(m.stack)-1]
+		switch e.kind {
+		case choiceEntry:
+			m.values, m.marks, m.quiet = m.values[:e.values], m.marks[:e.marks], e.quiet
+			return e.pc, e.pos, nil
+		case callEntry:
+			m.quiet = e.quiet
+			if description := m.program.rules[e.rule].description; description != "" {
+				m.record(e.pos, description)
+			}
+		case barrierEntry:
+			return 0, 0, m.report(pos)
+		}
+	}
+	return 0, 0, m.report(pos)
+}
+
+/*
+Returns the names of the rules being parsed, innermost first.
+*/
+func (m *machine) rules() []string {
+	var names []string
+	for i := len(m.stack) - 1; i >= 0; i-- {
+		if m.stack[i].kind == callEntry {
+			names = append(names, m.program.rules[m.stack[i].rule].name)
+		}
+	}
+	return names
+}
+
+/*
+Records that expected was required at the position, unless quiet. Without a
+cache the machine may fail the same way more than once; only the first failure
+is recorded, as Parse would.
+*/
+func (m *machine) record(pos int, expected string) {
+	if f := m.farthest.err; m.quiet > 0 || f != nil && (m.offsets[pos] < f.Offset || m.offsets[pos] == f.Offset && slices.Contains(f.Expected, expected)) {
+		return
+	}
+	failure := &ParseError{m.lines[pos], m.columns[pos], m.offsets[pos], []string{expected}, m.rules(), nil}
+	m.farthest.err = m.farthest.err.merge(failure)
+}
+
+/*
+Returns the error for a failed parse.
+*/
+func (m *machine) report(pos int) error {
+	if m.farthest.err != nil {
+		return m.farthest.err
+	}
+	return &ParseError{m.lines[pos], m.columns[pos], m.offsets[pos], nil, nil, nil}
+}
+
+/*
+Creates a new parser running on the parsing machine, like NewParser.
+*/
+func NewProgramParser[T any](root string, grammar string, handler any) Parser[T] {
+	parser := NewProgramParserFrom(grammar, handler)
+	return func(input string) (T, error) {
+		result, err := parser(root, input)
+		if err != nil || result == nil {
+			var t T
+			return t, err
+		}
+		return result.(T), nil
+	}
+}
+
+/*
+Creates a new parser for any root rule running on the parsing machine, like
+NewParserFrom. Problems with the grammar are reported by every call.
+*/
+func NewProgramParserFrom(grammar string, handler any) ParserFrom {
+	rules, err := bootstrapValid(grammar, handler)
+	var program *Program
+	if err == nil {
+		program, err = NewProgram(rules)
+	}
+	realHandler := WrapSpanHandler(handler)
+	return func(root, input string) (any, error) {
+		if err != nil {
+			return nil, err
+		}
+		return program.Parse(root, realHandler, input)
+	}
+}
@@ -0,0 +1,98 @@
+package parser_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+const machineGrammar = `
+%skip = [ \t\n] / Comment
+%lexical Word Num
+S = Item (',' ^ Item)* !.
+Item = Pair / List<Num, ';'> / Code / Folded / Cap / Word
+Pair = key:Word '=' value:(Num / Word)
+List<X, Sep> = '[' X (Sep X)* ']'
+Word = [a-z]+
+Num = [0-9]{1,3} &[^0-9]
+Code = '#' [A-F]{2} ([A-F]{2})?
+Folded "yes or no" = 'yes'i / &'n' 'no'
+Cap = '@' $(. .)
+Comment = '/*' (!'*/' .)* '*/'
+`
+
+func TestProgramIdentical(t *testing.T) {
+	rules := when.YouErr(parser.Bootstrap(machineGrammar)).ExpectSuccess(t)
+	program := when.YouErr(parser.NewProgram(rules)).ExpectSuccess(t)
+	inputs := []string{"a", " a = b , c=12 ,YeS,no", "[1; 22 ;333]", "#AB,#ABCD,#ABC", "@xy /* c */, d",
+		"12", "1234", "a,", "nope", "a=", "[1;]", "a b", "@x", ""}
+	for _, handler := range []parser.SpanHandler{parser.WrapSpanHandler(nil), parser.TreeHandler} {
+		for _, input := range inputs {
+			expected, expectedErr := parser.Parse("S", rules, handler, input)
+			actual, actualErr := program.Parse("S", handler, input)
+			when.You(actual).Expect(t, expected)
+			when.You(actualErr).Expect(t, expectedErr)
+		}
+	}
+}
+
+func TestProgramPeg(t *testing.T) {
+	peg := when.YouErr(os.ReadFile("../sample/peg.peg")).ExpectSuccess(t)
+	program := when.YouErr(parser.NewProgram(parser.PegGrammar())).ExpectSuccess(t)
+	inputs := []string{string(peg), machineGrammar, structGrammar, "S = 'a' / ", "S = [a-", "S = 'a\nT = 'b'", "%import"}
+	for _, input := range inputs {
+		expected, expectedErr := parser.Parse("Grammar", parser.PegGrammar(), parser.PegHandler, input)
+		actual, actualErr := program.Parse("Grammar", parser.PegHandler, input)
+		if expectedErr != nil {
+			when.You(actual).Expect(t, expected)
+			when.You(actualErr).ExpectMatch(t, func(t *testing.T, actual error) bool {
+				return when.AssertError(t, actual, expectedErr.Error())
+			})
+			continue
+		}
+		when.You(actualErr).Expect(t, nil)
+		when.You(actual.(*parser.Grammar).String()).Expect(t, expected.(*parser.Grammar).String())
+	}
+}
+
+func TestProgramParser(t *testing.T) {
+	parse := parser.NewProgramParser[string]("Sum", "%skip = ' '\nSum = [0-9] ('+' [0-9])*", nil)
+	when.YouErr(parse(" 1 + 2+3 ")).Expect(t, "1+2+3")
+	when.YouErr(parse("+")).ExpectError(t, "at 1:1 expected [0-9]\nwhile in Sum")
+
+	failing := parser.NewProgramParser[any]("S", "S = A\nA = 'a'", map[string]parser.Converter{"B": nil})
+	when.YouErr(failing("a")).ExpectError(t, "unmatched handler in B: no rule named B")
+
+	from := parser.NewProgramParserFrom("S = 'a' T\nT = 'b'", nil)
+	when.YouErr(from("T", "b")).Expect(t, "b")
+	when.YouErr(from("U", "b")).ExpectError(t, "no such rule: U")
+}
+
+func TestProgramUnsupported(t *testing.T) {
+	grammars := map[string][]string{
+		"S = S 'a' / 'a'": {"aaa", "b"},
+		"S = A+\nA = [a-z]+ / ' '\n%token A\n%ignore B\nB = ' '": {"ab cd", "1"},
+		"S = $x('a'+) 'b' $x":   {"aabaa", "aaba"},
+		"S = 'a' INDENT 'b'":    {"a\n  b", "ab"},
+		"S = 'a' ('b' / ^) 'c'": {"abc", "ac", "ad"},
+	}
+	for grammar, inputs := range grammars {
+		rules := when.YouErr(parser.Bootstrap(grammar)).ExpectSuccess(t)
+		program := when.YouErr(parser.NewProgram(rules)).ExpectSuccess(t)
+		when.You(program.Machine()).Expect(t, false)
+		for _, input := range inputs {
+			expected, expectedErr := parser.Parse("S", rules, nil, input)
+			actual, actualErr := program.Parse("S", nil, input)
+			when.You(actual).Expect(t, expected)
+			when.You(actualErr).Expect(t, expectedErr)
+		}
+	}
+	when.You(when.YouErr(parser.NewProgram(parser.PegGrammar())).ExpectSuccess(t).Machine()).Expect(t, true)
+
+	parse := parser.NewProgramParser[string]("S", "S = S 'a' / 'a'", nil)
+	when.YouErr(parse("aa")).Expect(t, "aa")
+	undefined := when.YouErr(parser.Bootstrap("S = S 'a' / B")).ExpectSuccess(t)
+	when.YouErr(parser.NewProgram(undefined)).ExpectError(t, "rule S: no such rule: B")
+}
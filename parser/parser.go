@@ -19,87 +19,241 @@ type ruleStack struct {
 	next *ruleStack
 }
 
+/*
+Identifies the memoized results of a reference: by the memo id of a compiled
+grammar, or by key, with an id of -1, in a grammar that is not compiled.
+*/
+type memoKey struct {
+	key string
+	id  int
+}
+
+/*
+The memo keys of the rules being parsed at a position, for left recursion.
+*/
+type memoStack struct {
+	key  memoKey
+	next *memoStack
+}
+
 /*
 parseCache is a simple named tuple for partial packrat functionality.
 */
 type parseCache struct {
 	value      any
+	recovered  []*ErrorNode
 	err        error
 	end        *ParsePosition
 	pending    bool
 	lrDetected bool
-	paths      []string
+	paths      []memoKey
 }
 
-func newCache() *parseCache {
-	return &parseCache{nil, errors.New("left recursion detected"), nil, true, false, ([]string)(nil)}
+var errLeftRecursion = errors.New("left recursion detected")
+
+func newCache(at *ParsePosition) *parseCache {
+	return &parseCache{nil, nil, newParseError(at, errLeftRecursion), nil, true, false, nil}
 }
 
 /*
-ParsePosition is the mark object. There are no public fields or methods on
-this type.
+ParsePosition is the mark object. There are no public fields on this type,
+but it reports where in the input it is.
 */
 type ParsePosition struct {
 	grapheme *Grapheme
 	cache    map[string]*parseCache
-	stack    *ruleStack
+	memo     []*parseCache
+	stack    *memoStack
 	next     *ParsePosition
+	offset   int
+	bindings *binding
+	indents  *indent
+	stream   *tokenStream
 }
 
 // currently implemented as a linked list to track the current grapheme and
-// associated cached Rule results for this position
+// associated cached Rule results for this position. Compiled grammars memoize
+// into a slice indexed by memo id, grown only as far as the ids used at the
+// position, others into a map by key.
 
 /*
 Creates the initial ParsePostion. Further Positions should be created from
 advance().
 */
 func newParsePosition(input string) *ParsePosition {
-	return &ParsePosition{NewGrapheme(input), make(map[string]*parseCache), nil, nil}
+	return &ParsePosition{NewGrapheme(input), nil, nil, nil, nil, 0, nil, nil, nil}
+}
+
+/*
+A text captured by name with Bind, shared by every position after it.
+*/
+type binding struct {
+	name, text string
+	next       *binding
+}
+
+/*
+A column pushed by INDENT, shared by every position after it.
+*/
+type indent struct {
+	column int
+	next   *indent
+}
+
+/*
+Returns a position at the same place in the input with different bindings and
+indents. The position starts a new list with its own cache, so backtracking to
+an earlier mark restores the old state, and memoized results are never shared
+between different states.
+*/
+func (p *ParsePosition) fork(bindings *binding, indents *indent) *ParsePosition {
+	return &ParsePosition{p.grapheme, nil, nil, nil, nil, p.offset, bindings, indents, p.stream}
+}
+
+/*
+Returns a position with the name bound to the text.
+*/
+func (p *ParsePosition) bind(name, text string) *ParsePosition {
+	return p.fork(&binding{name, text, p.bindings}, p.indents)
+}
+
+/*
+Returns the current indentation column, 1 when nothing has been indented.
+*/
+func (p *ParsePosition) indentation() int {
+	if p.indents == nil {
+		return 1
+	}
+	return p.indents.column
+}
+
+/*
+Returns the text most recently bound to the name at this position.
+*/
+func (p *ParsePosition) bound(name string) (string, bool) {
+	for b := p.bindings; b != nil; b = b.next {
+		if b.name == name {
+			return b.text, true
+		}
+	}
+	return "", false
+}
+
+/*
+Returns the cached results for the key, or nil if there are none.
+*/
+func (p *ParsePosition) lookup(key memoKey) *parseCache {
+	if key.id < 0 {
+		return p.cache[key.key]
+	}
+	if key.id < len(p.memo) {
+		return p.memo[key.id]
+	}
+	return nil
+}
+
+/*
+Caches results for the key.
+*/
+func (p *ParsePosition) store(key memoKey, cached *parseCache) {
+	if key.id < 0 {
+		if p.cache == nil {
+			p.cache = make(map[string]*parseCache)
+		}
+		p.cache[key.key] = cached
+		return
+	}
+	if key.id >= len(p.memo) {
+		p.memo = slices.Grow(p.memo, key.id+1-len(p.memo))[:key.id+1]
+	}
+	p.memo[key.id] = cached
+}
+
+/*
+Drops the cached results for the key.
+*/
+func (p *ParsePosition) drop(key memoKey) {
+	if key.id < 0 {
+		delete(p.cache, key.key)
+		return
+	}
+	if key.id < len(p.memo) {
+		p.memo[key.id] = nil
+	}
 }
 
 /*
-Gets a cached result & end mark for a given ref name, if one exists. Return indicates a cache hit.
+Gets a cached result, recovered errors & end mark for a given memo key, if one
+exists. Return indicates a cache hit.
 */
-func (p *ParsePosition) get(name string) (result any, err error, end *ParsePosition, exists bool) {
-	cached, exists := p.cache[name]
+func (p *ParsePosition) get(key memoKey) (result any, recovered []*ErrorNode, err error, end *ParsePosition, exists bool) {
+	cached := p.lookup(key)
+	exists = cached != nil
 	if !exists {
-		p.stack = &ruleStack{name, p.stack}
-		cached = newCache()
-		p.cache[name] = cached
+		p.stack = &memoStack{key, p.stack}
+		cached = newCache(p)
+		p.store(key, cached)
 	} else if cached.pending {
 		cached.lrDetected = true
-		for c := p.stack; c.name != name; c = c.next {
-			cached.paths = append(cached.paths, c.name)
+		for c := p.stack; c.key != key; c = c.next {
+			cached.paths = append(cached.paths, c.key)
 		}
 	}
-	return cached.value, cached.err, cached.end, exists
+	return cached.value, cached.recovered, cached.err, cached.end, exists
 }
 
 /*
-Caches a result and end mark for a given ref name. Returns true if ref should recurse.
+Caches a result, recovered errors and end mark for a given memo key. Returns the best result so far,
+and true if ref should recurse.
 */
-func (p *ParsePosition) put(name string, result any, err error, end *ParsePosition) (any, error, bool) {
-	cached := p.cache[name]
+func (p *ParsePosition) put(key memoKey, result any, recovered []*ErrorNode, err error, end *ParsePosition) (any, []*ErrorNode, error, *ParsePosition, bool) {
+	cached := p.lookup(key)
 	first := cached.end == nil
 	failed := err != nil
 	advanced := first || (!failed && cached.end.grapheme.Pos < end.grapheme.Pos)
 	detected := advanced && cached.lrDetected
 	if advanced {
 		cached.value = result
+		cached.recovered = recovered
 		cached.err = err
 		cached.end = end
 	}
 	if detected {
 		for _, n := range cached.paths {
-			delete(p.cache, n)
+			p.drop(n)
 		}
-		cached.paths = ([]string)(nil)
+		cached.paths = nil
 		cached.lrDetected = false
 	} else {
 		p.stack = p.stack.next
 		cached.pending = false
 	}
-	return cached.value, cached.err, cached.pending
+	return cached.value, cached.recovered, cached.err, cached.end, cached.pending
+}
+
+/*
+Returns the 1-based line of this position.
+*/
+func (p *ParsePosition) Line() int {
+	return p.grapheme.Line
+}
+
+/*
+Returns the 1-based column of this position.
+*/
+func (p *ParsePosition) Column() int {
+	return p.grapheme.Column
+}
+
+/*
+Returns the byte offset of this position into the input.
+*/
+func (p *ParsePosition) Offset() int {
+	return p.offset
+}
+
+func (p *ParsePosition) String() string {
+	return fmt.Sprintf("%d:%d", p.grapheme.Line, p.grapheme.Column)
 }
 
 /*
@@ -108,34 +262,95 @@ Advances to next position, creating it if necessary.
 func (p *ParsePosition) advance() (*ParsePosition, error) {
 	if p.next == nil {
 		if p.grapheme.IsEof() {
-			return nil, p.grapheme.Error("anything")
+			return nil, p.Error("anything")
+		}
+		if p.stream != nil {
+			p.next = p.stream.position(p.grapheme.Pos+1, p.bindings, p.indents)
+		} else {
+			p.next = &ParsePosition{p.grapheme.Next(), nil, nil, nil, nil, p.offset + len(p.grapheme.Token), p.bindings, p.indents, nil}
 		}
-		p.next = &ParsePosition{p.grapheme.Next(), make(map[string]*parseCache), nil, nil}
 	}
 	return p.next, nil
 }
 
 /*
-Returns an error instance that includes the parse position information.
+Returns a ParseError that includes the parse position information.
 */
 func (p *ParsePosition) Error(expected string) error {
-	return p.grapheme.Error(expected)
+	return newParseError(p, nil, expected)
 }
 
 /*
 ParseContext contains the state of a parse.
 */
 type ParseContext struct {
-	current *ParsePosition
-	grammar *Grammar
-	handler Handler
+	input      string
+	current    *ParsePosition
+	grammar    *Grammar
+	handler    SpanHandler
+	rules      *ruleStack
+	farthest   farthest
+	quiet      int
+	recovering bool
+	tracer     Tracer
+	profile    *Profile
+	instances  map[string]*Rule
+	cut        bool
+	released   *ParsePosition
+	forks      []*ParsePosition
+	exactRules map[string]bool
+	exact      int
 }
 
 /*
 Create a new ParseContext from the input, rules, and converters.
 */
-func newParseContext(input string, grammar *Grammar, handler Handler) *ParseContext {
-	return &ParseContext{newParsePosition(input), grammar, handler}
+func newParseContext(input string, grammar *Grammar, handler SpanHandler) *ParseContext {
+	start := newParsePosition(input)
+	return &ParseContext{input, start, grammar, handler, nil, farthest{}, 0, false, nil, nil, make(map[string]*Rule), false, start, nil, grammar.exactRules(), 0}
+}
+
+/*
+Returns the memo key for the results of a reference, by its key. A compiled
+grammar has a memo id for every key it uses.
+*/
+func (c *ParseContext) memoKey(key string) memoKey {
+	if id, ok := c.grammar.ids[key]; ok {
+		return memoKey{key, id}
+	}
+	return memoKey{key, -1}
+}
+
+/*
+Returns the rule a reference names, instantiating parameterized rules with the
+reference's arguments. Instances are kept for the rest of the parse. A
+compiled reference already knows its rule.
+*/
+func (c *ParseContext) rule(ref *Reference) (*Rule, error) {
+	if ref.resolved != nil {
+		return ref.resolved.rule, nil
+	}
+	rule := c.grammar.Rule(ref.name)
+	if rule == nil {
+		rule = builtins[ref.name]
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("no such rule: %s", ref.name)
+	}
+	if len(ref.args) == 0 && len(rule.params) == 0 {
+		return rule, nil
+	}
+	key := ref.key()
+	instance, ok := c.instances[key]
+	if !ok {
+		var err error
+		instance, err = rule.instantiate(ref.args)
+		if err != nil {
+			return nil, err
+		}
+		c.instances[key] = instance
+	}
+	return instance, nil
 }
 
 /*
@@ -145,8 +360,11 @@ func (c *ParseContext) Mark() *ParsePosition {
 	return c.current
 }
 
+/*
+Returns true if the parse has not moved past the mark.
+*/
 func (c *ParseContext) At(mark *ParsePosition) bool {
-	return c.current == mark
+	return c.current.offset == mark.offset
 }
 
 /*
@@ -157,6 +375,43 @@ func (c *ParseContext) Reset(mark *ParsePosition) {
 	c.current = mark
 }
 
+/*
+Moves the parse to a fork of the current position, remembering the fork so
+the next release reaches the positions after it as well.
+*/
+func (c *ParseContext) fork(p *ParsePosition) {
+	c.forks = append(c.forks, p)
+	c.current = p
+}
+
+/*
+Drops the memoized results between the last release and the current position,
+and the links from each of those positions to the next, so the positions can
+be collected even while an earlier mark is held. Positions after a fork are on
+a list of their own, so each fork since the last release is released too.
+Results of rules still being parsed are kept for left recursion. Backtracking
+behind a cut stays correct, it just advances and parses again.
+*/
+func (c *ParseContext) release() {
+	for _, start := range append(c.forks, c.released) {
+		for p := start; p != nil && p.offset < c.current.offset; {
+			for key, cached := range p.cache {
+				if !cached.pending {
+					delete(p.cache, key)
+				}
+			}
+			for id, cached := range p.memo {
+				if cached != nil && !cached.pending {
+					p.memo[id] = nil
+				}
+			}
+			p, p.next = p.next, nil
+		}
+	}
+	c.forks = nil
+	c.released = c.current
+}
+
 /*
 Returns the token at the current parse position.
 */
@@ -165,21 +420,48 @@ func (c *ParseContext) Token() string {
 }
 
 /*
-Returns an error instance that includes the parse position information.
+Returns a ParseError that includes the parse position information. The
+expectation is recorded for farthest-failure reporting.
 */
 func (c *ParseContext) Error(expected string) error {
-	return c.current.Error(expected)
+	return c.fail(c.current, expected)
+}
+
+/*
+Reports a terminal match or failure from the start position to the tracer, if
+there is one. Returns err.
+*/
+func (c *ParseContext) trace(expr Expr, start *ParsePosition, err error) error {
+	if c.tracer != nil {
+		if err != nil {
+			c.tracer.Fail(expr, start, err)
+		} else {
+			c.tracer.Match(expr, start, c.current)
+		}
+	}
+	return err
+}
+
+/*
+Records that expected was required at the position, unless inside a
+lookahead or described rule, and returns the corresponding ParseError.
+*/
+func (c *ParseContext) fail(at *ParsePosition, expected string) error {
+	if c.quiet == 0 {
+		c.farthest.record(at, c.rules, expected)
+	}
+	return newParseError(at, nil, expected)
 }
 
 /*
 Returns a substring from the input from the start position to the current position.
 */
 func (c *ParseContext) Substring(start *ParsePosition) string {
-	var sb strings.Builder
-	for p := start; p != c.current; p = p.next {
-		sb.WriteString(p.grapheme.Token)
+	end := c.current.offset
+	if c.lexed() && c.current.grapheme.Pos > start.grapheme.Pos {
+		end = c.current.stream.tokens[c.current.grapheme.Pos-1].Span.EndOffset
 	}
-	return sb.String()
+	return c.input[start.offset:end]
 }
 
 /*
@@ -187,9 +469,12 @@ Accepts the current grapheme and advances to the next one. EOF returns a
 non-nil error; other errors may be possible.
 */
 func (c *ParseContext) Next() error {
-	var err error
-	c.current, err = c.current.advance()
-	return err
+	next, err := c.current.advance()
+	if err != nil {
+		return c.fail(c.current, "anything")
+	}
+	c.current = next
+	return nil
 }
 
 /*
@@ -248,24 +533,65 @@ Converts a parse result to an output object.
 */
 type Converter func(iter.Seq2[string, any]) (any, error)
 
+/*
+Converts a parse result to an output object, given the span of input the rule
+matched.
+*/
+type SpanConverter func(iter.Seq2[string, any], Span) (any, error)
+
+/*
+Returns a SpanConverter that ignores the span, or nil for a nil converter.
+*/
+func (c Converter) withSpan() SpanConverter {
+	if c == nil {
+		return nil
+	}
+	return func(result iter.Seq2[string, any], _ Span) (any, error) {
+		return c(result)
+	}
+}
+
 /*
 Returns a converter for a given rule name.
 */
 type Handler func(string) Converter
 
 /*
-Uses methods on a type to generate a handler.
+Returns a converter for a given rule name that is also given the span of the
+rule.
+*/
+type SpanHandler func(string) SpanConverter
+
+var spanType = reflect.TypeFor[Span]()
+
+/*
+Uses methods on a type to generate a handler. Qualified rules like json.String
+are handled by the method for the unqualified name. Methods that take a Span
+are given an empty one; use ReflectSpanHandler to pass the spans along.
 */
 func ReflectHandler(handler any) Handler {
+	return withoutSpans(ReflectSpanHandler(handler))
+}
+
+/*
+Uses methods on a type to generate a SpanHandler. Qualified rules like
+json.String are handled by the method for the unqualified name. A method may
+take the Span of the rule as a second argument; a method not shaped like a
+Converter or SpanConverter handles nothing.
+*/
+func ReflectSpanHandler(handler any) SpanHandler {
 	value := reflect.ValueOf(handler)
-	return func(name string) Converter {
-		method := value.MethodByName(name)
-		if !method.IsValid() {
+	return func(name string) SpanConverter {
+		method := value.MethodByName(name[strings.LastIndex(name, ".")+1:])
+		if !method.IsValid() || !isConverter(method.Type()) {
 			return nil
 		}
-		return func(result iter.Seq2[string, any]) (any, error) {
-			methodArgs := make([]reflect.Value, 1)
-			methodArgs[0] = reflect.ValueOf(result)
+		withSpan := method.Type().NumIn() == 2 && method.Type().In(1) == spanType
+		return func(result iter.Seq2[string, any], span Span) (any, error) {
+			methodArgs := []reflect.Value{reflect.ValueOf(result)}
+			if withSpan {
+				methodArgs = append(methodArgs, reflect.ValueOf(span))
+			}
 			returns := method.Call(methodArgs)
 			err := returns[1].Interface()
 			if err == nil {
@@ -277,91 +603,228 @@ func ReflectHandler(handler any) Handler {
 }
 
 /*
-Wraps a map, struct, or nil into a valid handler.
+Wraps a map, struct, handler, or nil into a valid Handler. Converters that
+take a Span are given an empty one; use WrapSpanHandler to pass the spans
+along.
 */
 func WrapHandler(handler any) Handler {
-	if handler == nil {
-		return func(string) Converter {
+	switch h := handler.(type) {
+	case Handler:
+		return h
+	case func(string) Converter:
+		return h
+	}
+	return withoutSpans(WrapSpanHandler(handler))
+}
+
+/*
+Wraps a map, struct, handler, or nil into a valid SpanHandler. Maps and handler
+functions may use either Converter or SpanConverter.
+*/
+func WrapSpanHandler(handler any) SpanHandler {
+	switch h := handler.(type) {
+	case nil:
+		return func(string) SpanConverter {
 			return nil
 		}
-	}
-	h, ok := handler.(Handler)
-	if ok {
+	case SpanHandler:
+		return h
+	case func(string) SpanConverter:
 		return h
+	case Handler:
+		return func(key string) SpanConverter {
+			return h(key).withSpan()
+		}
+	case func(string) Converter:
+		return func(key string) SpanConverter {
+			return h(key).withSpan()
+		}
+	case map[string]Converter:
+		return func(key string) SpanConverter {
+			return h[key].withSpan()
+		}
+	case map[string]SpanConverter:
+		return func(key string) SpanConverter {
+			return h[key]
+		}
+	case *TypeHandler:
+		return h.Converter
 	}
-	m, ok := handler.(map[string]Converter)
-	if ok {
-		return func(key string) Converter {
-			return m[key]
+	return ReflectSpanHandler(handler)
+}
+
+/*
+Returns a Handler whose converters are given an empty Span.
+*/
+func withoutSpans(handler SpanHandler) Handler {
+	return func(key string) Converter {
+		converter := handler(key)
+		if converter == nil {
+			return nil
+		}
+		return func(result iter.Seq2[string, any]) (any, error) {
+			return converter(result, Span{})
 		}
 	}
-	return ReflectHandler(handler)
 }
 
 /*
 Defines a grammar Rule.
 */
 type Rule struct {
-	name string
-	expr Expr
+	name        string
+	expr        Expr
+	description string
+	params      []string
+	lexical     lexical
+}
+
+/*
+Creates a rule. A rule with params is instantiated by references that pass
+an argument for each, like List<Value, ','>.
+*/
+func NewRule(name string, expr Expr, params ...string) *Rule {
+	return &Rule{name, expr, "", params, syntactic}
+}
+
+/*
+Sets the human-readable description of the rule. Failures inside a described
+rule are reported as the description instead of the rule's internals.
+*/
+func (r *Rule) Describe(description string) *Rule {
+	r.description = description
+	return r
 }
 
 /*
-Creates a rule.
+Returns the description of the rule, or the empty string if it has none.
 */
-func NewRule(name string, expr Expr) *Rule {
-	return &Rule{name, expr}
+func (r *Rule) Description() string {
+	return r.description
+}
+
+/*
+Returns the parameters of the rule.
+*/
+func (r *Rule) Params() []string {
+	return r.params
+}
+
+/*
+Returns a copy of the rule with every reference to a parameter replaced by the
+corresponding argument. The copy keeps the rule's name, so its results and
+handler are the same for every instance.
+*/
+func (r *Rule) instantiate(args []Expr) (*Rule, error) {
+	if len(args) != len(r.params) {
+		return nil, fmt.Errorf("rule %s takes %d arguments, got %d", r.name, len(r.params), len(args))
+	}
+	expr := transform(r.expr, func(expr Expr) Expr {
+		if ref, ok := expr.(*Reference); ok && len(ref.args) == 0 {
+			if i := slices.Index(r.params, ref.name); i >= 0 {
+				return args[i]
+			}
+		}
+		return expr
+	})
+	return &Rule{r.name, expr, r.description, nil, r.lexical}, nil
 }
 
 /*
 Parses the input and returns a converted output object.
 */
 func (r *Rule) Parse(context *ParseContext) (any, error) {
+	value, _, err := r.parse(context, nil)
+	return value, err
+}
+
+/*
+Parses the input and returns a converted output object, along with any error
+nodes recovered from while parsing the rule. A generated body parses in place
+of the rule's expression.
+*/
+func (r *Rule) parse(context *ParseContext, body ParseFunc) (any, []*ErrorNode, error) {
+	if (r.lexical == token || r.lexical == ignored) && context.lexed() {
+		return r.parseToken(context)
+	}
+	if context.exactRules[r.name] {
+		context.exact++
+		defer func() { context.exact-- }()
+	}
+	mark := context.Mark()
 	converter := context.handler(r.name)
-	result, err := r.expr.Parse(context)
+	context.rules = &ruleStack{r.name, context.rules}
+	var result *ParseResult
+	var err error
+	if body != nil {
+		result, err = body(context)
+	} else {
+		result, err = r.expr.Parse(context)
+	}
+	context.rules = context.rules.next
 	if err != nil {
-		return nil, fmt.Errorf("%s\nwhile in %s", err, r.name)
+		return nil, nil, asParseError(err, mark).within(r.name)
 	}
+	recovered := slices.Collect(funki.Cast[*ErrorNode](funki.Values(funki.FilterKeys(result.Results(), ErrorKey))))
 	if converter != nil {
-		return converter(result.Results())
-	}
-	var sb strings.Builder
-	for _, value := range result.Results() {
-		sb.WriteString(fmt.Sprint(value))
+		value, err := converter(result.Results(), context.spanFrom(mark))
+		if err != nil {
+			return nil, nil, asParseError(err, mark).within(r.name)
+		}
+		return value, recovered, nil
 	}
-	return sb.String(), nil
+	return concat(result.Results()), recovered, nil
 }
 
 func (r *Rule) String() string {
-	return fmt.Sprintf("Rule(\"%s\", %s)", r.name, r.expr)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Rule(\"%s\", %s", r.name, r.expr)
+	for _, param := range r.params {
+		fmt.Fprintf(&sb, ", \"%s\"", param)
+	}
+	sb.WriteString(")")
+	if r.description != "" {
+		fmt.Fprintf(&sb, ".Describe(\"%s\")", r.description)
+	}
+	return sb.String()
 }
 
 /*
 Represents the collection of rules that specifies a grammar.
 */
 type Grammar struct {
-	rules map[string]*Rule
-	order []string
+	rules    map[string]*Rule
+	order    []string
+	imported map[string]bool
+	skip     Expr
+	ids      map[string]int
+	refs     []*Reference
 }
 
 /*
 Creates an empty grammar.
 */
 func NewGrammar() *Grammar {
-	return &Grammar{make(map[string]*Rule), make([]string, 0)}
+	return &Grammar{make(map[string]*Rule), make([]string, 0), make(map[string]bool), nil, nil, nil}
 }
 
 /*
 Creates and adds a rule to the grammar.
 */
-func (g *Grammar) AddRule(name string, expr Expr) *Grammar {
-	return g.Add(NewRule(name, expr))
+func (g *Grammar) AddRule(name string, expr Expr, params ...string) *Grammar {
+	return g.Add(NewRule(name, expr, params...))
 }
 
 /*
-Adds a rule to the grammar.
+Adds a rule to the grammar. A rule with the name of an imported rule
+overrides it in place.
 */
 func (g *Grammar) Add(rule *Rule) *Grammar {
+	if g.imported[rule.name] {
+		delete(g.imported, rule.name)
+		g.rules[rule.name] = rule
+		return g
+	}
 	g.rules[rule.name] = rule
 	g.order = append(g.order, rule.name)
 	return g
@@ -399,7 +862,23 @@ type ParserFrom func(root, input string) (any, error)
 Creates a new parser.
 */
 func NewParser[T any](root string, grammar string, handler any) Parser[T] {
-	parser := NewParserFrom(grammar, handler)
+	return typedParser[T](root, NewParserFrom(grammar, handler))
+}
+
+/*
+Creates a new parser, returning the problems that keep the grammar from
+parsing correctly, like undefined rules, instead of reporting them from every
+call.
+*/
+func CompileParser[T any](root string, grammar string, handler any) (Parser[T], error) {
+	parser, err := CompileParserFrom(grammar, handler)
+	if err != nil {
+		return nil, err
+	}
+	return typedParser[T](root, parser), nil
+}
+
+func typedParser[T any](root string, parser ParserFrom) Parser[T] {
 	return func(input string) (T, error) {
 		result, err := parser(root, input)
 		if err != nil {
@@ -414,20 +893,74 @@ func NewParser[T any](root string, grammar string, handler any) Parser[T] {
 	}
 }
 
+/*
+Creates a new parser for any root rule. Problems that keep the grammar from
+parsing correctly, like undefined rules, are reported by every call.
+*/
 func NewParserFrom(grammar string, handler any) ParserFrom {
-	rules, err := Bootstrap(grammar)
-	realHandler := WrapHandler(handler)
-	return func(root, input string) (any, error) {
-		if err != nil {
+	parser, err := CompileParserFrom(grammar, handler)
+	if err != nil {
+		return func(root, input string) (any, error) {
 			return nil, err
 		}
+	}
+	return parser
+}
+
+/*
+Creates a new parser for any root rule, returning the problems that keep the
+grammar from parsing correctly instead of reporting them from every call.
+*/
+func CompileParserFrom(grammar string, handler any) (ParserFrom, error) {
+	rules, err := bootstrapValid(grammar, handler)
+	if err != nil {
+		return nil, err
+	}
+	realHandler := WrapSpanHandler(handler)
+	return func(root, input string) (any, error) {
 		return Parse(root, rules, realHandler, input)
+	}, nil
+}
+
+/*
+Creates a new parser that recovers from failures inside Recover expressions.
+The best-effort result is returned even when the error is non-nil.
+*/
+func NewRecoveringParser[T any](root string, grammar string, handler any) Parser[T] {
+	rules, err := bootstrapValid(grammar, handler)
+	realHandler := WrapSpanHandler(handler)
+	return func(input string) (T, error) {
+		var t T
+		if err != nil {
+			return t, err
+		}
+		result, err := Parse(root, rules, realHandler, input, WithRecovery())
+		if result != nil {
+			t = result.(T)
+		}
+		return t, err
 	}
 }
 
-func BootstrapParser[T any](root string, grammar *Grammar, handler Handler) Parser[T] {
+/*
+Bootstraps and compiles the grammar, failing on any fatal problem found by
+Validate or CheckHandler.
+*/
+func bootstrapValid(grammar string, handler any) (*Grammar, error) {
+	rules, err := Bootstrap(grammar)
+	if err != nil {
+		return nil, err
+	}
+	if err := slices.Concat(rules.Validate(), rules.CheckHandler(handler)).Err(); err != nil {
+		return nil, err
+	}
+	return rules.Compile()
+}
+
+func BootstrapParser[T any](root string, grammar *Grammar, handler any) Parser[T] {
+	realHandler := WrapSpanHandler(handler)
 	return func(input string) (T, error) {
-		result, err := Parse(root, grammar, handler, input)
+		result, err := Parse(root, grammar, realHandler, input)
 		if err != nil {
 			var t T
 			return t, err
@@ -436,20 +969,93 @@ func BootstrapParser[T any](root string, grammar *Grammar, handler Handler) Pars
 	}
 }
 
-func BootstrapParserFrom(grammar *Grammar, handler Handler) ParserFrom {
+func BootstrapParserFrom(grammar *Grammar, handler any) ParserFrom {
+	realHandler := WrapSpanHandler(handler)
 	return func(root, input string) (any, error) {
-		return Parse(root, grammar, handler, input)
+		return Parse(root, grammar, realHandler, input)
 	}
 }
 
 /*
-Parses the input according to the root, grammar, and handler.
+ParseOption changes how Parse runs. Options combine, so a parse may be traced,
+recovering, and profiled at once.
+*/
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	tracer     Tracer
+	recovering bool
+	profile    *Profile
+}
+
+/*
+Reports each step of the parse to the tracer.
 */
-func Parse(root string, grammar *Grammar, handler Handler, input string) (any, error) {
+func WithTracer(tracer Tracer) ParseOption {
+	return func(o *parseOptions) {
+		o.tracer = tracer
+	}
+}
+
+/*
+Recovers from failures inside Recover expressions. Parse returns the
+best-effort result, along with a Diagnostics error listing every recovered
+failure. If the parse cannot be recovered, the result is nil and the final
+failure ends the Diagnostics.
+*/
+func WithRecovery() ParseOption {
+	return func(o *parseOptions) {
+		o.recovering = true
+	}
+}
+
+/*
+Gathers per-rule statistics into the profile. The profile is filled in even
+if the parse fails.
+*/
+func WithProfile(profile *Profile) ParseOption {
+	return func(o *parseOptions) {
+		o.profile = profile
+	}
+}
+
+/*
+Parses the input according to the root, grammar, and handler. The handler is
+anything accepted by WrapSpanHandler.
+*/
+func Parse(root string, grammar *Grammar, handler any, input string, opts ...ParseOption) (any, error) {
+	var options parseOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 	ref := Ref(root)
-	result, err := ref.Parse(newParseContext(input, grammar, handler))
+	context, err := startParse(input, grammar, WrapSpanHandler(handler))
 	if err != nil {
+		if options.recovering {
+			return nil, Diagnostics{asParseError(err, newParsePosition(input))}
+		}
+		return nil, err
+	}
+	context.tracer = options.tracer
+	context.recovering = options.recovering
+	context.profile = options.profile
+	result, err := ref.Parse(context)
+	if err != nil {
+		err = context.farthest.report(err)
+		if options.recovering {
+			return nil, Diagnostics{asParseError(err, context.Mark())}
+		}
 		return nil, err
 	}
+	if !options.recovering {
+		return result.value, nil
+	}
+	var diagnostics Diagnostics
+	for node := range funki.Cast[*ErrorNode](funki.Values(funki.FilterKeys(result.Results(), ErrorKey))) {
+		diagnostics = append(diagnostics, node.Err)
+	}
+	if diagnostics != nil {
+		return result.value, diagnostics
+	}
 	return result.value, nil
 }
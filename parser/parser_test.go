@@ -2,6 +2,7 @@ package parser_test
 
 import (
 	"iter"
+	"maps"
 	"slices"
 	"testing"
 
@@ -81,6 +82,16 @@ func TestParserLeftRecursion(t *testing.T) {
 	})
 }
 
+func TestParserLeftRecursionContinues(t *testing.T) {
+	t.Run("Parser Left Recursion Continues", func(t *testing.T) {
+		grammar := parser.NewGrammar().AddRule("S", parser.Seq(parser.Ref("L"), parser.Lit("b")))
+		grammar.AddRule("L", parser.Alt(parser.Seq(parser.Ref("L"), parser.Lit("a")), parser.Lit("a")))
+		parser := parser.BootstrapParser[any]("S", grammar, parser.WrapHandler(nil))
+		when.YouErr(parser("ab")).Expect(t, "ab")
+		when.YouErr(parser("aaab")).Expect(t, "aaab")
+	})
+}
+
 func TestParserIndirectLeftRecursion(t *testing.T) {
 	t.Run("Parser Left Recursion", func(t *testing.T) {
 		grammar := parser.NewGrammar().AddRule("S", parser.Alt(parser.Seq(parser.Ref("T"), parser.Lit("b")), parser.Lit("a")))
@@ -91,3 +102,117 @@ func TestParserIndirectLeftRecursion(t *testing.T) {
 		when.YouErr(parser("aabab")).Expect(t, "aabab")
 	})
 }
+func TestParserParameterized(t *testing.T) {
+	t.Run("Parser Parameterized", func(t *testing.T) {
+		handler := make(map[string]parser.Converter)
+		handler["List"] = func(result iter.Seq2[string, any]) (any, error) {
+			return slices.Collect(funki.Values(funki.FilterKeys(result, "Num", "Word"))), nil
+		}
+		handler["S"] = func(result iter.Seq2[string, any]) (any, error) {
+			_, list := funki.FirstOf(result, "List", "Pair")
+			return list, nil
+		}
+		parse := parser.NewParser[any]("S", `
+S = List<Num, ','> '!' / List<Num, ';'> / Pair<Word>
+Pair<X> = '(' List<X, ' '> ')'
+List<Item, Sep> = Item (Sep Item)*
+Num = [0-9]+
+Word = [a-z]+
+`, handler)
+		when.YouErr(parse("1,22!")).Expect(t, []any{"1", "22"})
+		when.YouErr(parse("1;2;3")).Expect(t, []any{"1", "2", "3"})
+		when.YouErr(parse("(a bc)")).Expect(t, "(abc)")
+	})
+}
+func TestParserLabels(t *testing.T) {
+	t.Run("Parser Labels", func(t *testing.T) {
+		handler := make(map[string]parser.Converter)
+		handler["Range"] = func(result iter.Seq2[string, any]) (any, error) {
+			return maps.Collect(result), nil
+		}
+		parse := parser.NewParser[any]("Range", `
+Range = lo:Number dots:'..' hi:Number step:(',' Number)?
+Number = [0-9]+
+`, handler)
+		when.YouErr(parse("1..10")).Expect(t, map[string]any{"lo": "1", "dots": "..", "hi": "10", "step": nil})
+		when.YouErr(parse("1..10,2")).Expect(t, map[string]any{"lo": "1", "dots": "..", "hi": "10", "step": []any{",", "2"}})
+
+		digits := parser.NewParser[any]("S", "S = ds:[0-9]* one:([a-z])+", map[string]parser.Converter{"S": handler["Range"]})
+		when.YouErr(digits("12ab")).Expect(t, map[string]any{"ds": []any{"1", "2"}, "one": []any{"a", "b"}})
+		when.YouErr(digits("x")).Expect(t, map[string]any{"ds": nil, "one": "x"})
+
+		text := parser.NewParser[string]("S", "S = 'a' x:'b'? y:('c' 'd')?", nil)
+		when.YouErr(text("a")).Expect(t, "a")
+		when.YouErr(text("abcd")).Expect(t, "abcd")
+	})
+}
+func TestParserCapture(t *testing.T) {
+	t.Run("Parser Capture", func(t *testing.T) {
+		handler := make(map[string]parser.Converter)
+		handler["Assign"] = func(result iter.Seq2[string, any]) (any, error) {
+			return maps.Collect(funki.FilterKeys(result, "name", "value")), nil
+		}
+		handler["Num"] = func(result iter.Seq2[string, any]) (any, error) {
+			return len(slices.Collect(funki.Values(result))), nil
+		}
+		parse := parser.NewParser[any]("Assign", `
+Assign = name:$([a-z]+) [ \n]* '=' [ \n]* value:$(Num (',' Num)*)
+Num = [0-9]+
+`, handler)
+		when.YouErr(parse("ab =\n12,3")).Expect(t, map[string]any{
+			"name":  parser.Captured{"ab", parser.Span{1, 1, 0, 1, 3, 2}},
+			"value": parser.Captured{"12,3", parser.Span{2, 1, 5, 2, 5, 9}},
+		})
+
+		concat := parser.NewParser[string]("S", "S = $(T) '!'\nT = [a-z]+", nil)
+		when.YouErr(concat("abc!")).Expect(t, "abc!")
+	})
+}
+
+type spanHandler struct{}
+
+func (h spanHandler) S(result iter.Seq2[string, any], span parser.Span) (any, error) {
+	return slices.Collect(funki.Values(funki.FilterKeys(result, "T"))), nil
+}
+
+func (h spanHandler) T(result iter.Seq2[string, any], span parser.Span) (any, error) {
+	return span, nil
+}
+
+func (h spanHandler) U(result iter.Seq2[string, any]) (any, error) {
+	return "u", nil
+}
+
+func TestParserSpans(t *testing.T) {
+	t.Run("Parser Spans", func(t *testing.T) {
+		grammar := parser.NewGrammar().AddRule("S", parser.Seq(parser.Ref("T"), parser.Lit("\n"), parser.Ref("T"), parser.Ref("U"))).
+			AddRule("T", parser.Req(parser.Cls("[a-z]"))).AddRule("U", parser.Lit("!"))
+		parse := parser.BootstrapParser[any]("S", grammar, parser.WrapSpanHandler(spanHandler{}))
+		when.YouErr(parse("ab\ncde!")).Expect(t, []any{parser.Span{1, 1, 0, 1, 3, 2}, parser.Span{2, 1, 3, 2, 4, 6}})
+		when.YouErr(parser.ReflectSpanHandler(spanHandler{})("T")(nil, parser.Span{1, 1, 0, 1, 2, 1})).Expect(t, parser.Span{1, 1, 0, 1, 2, 1})
+		when.YouErr(parser.ReflectHandler(spanHandler{})("T")(nil)).Expect(t, parser.Span{})
+		when.YouErr(parser.ReflectHandler(spanHandler{})("U")(nil)).Expect(t, "u")
+
+		spans := map[string]parser.SpanConverter{"T": func(result iter.Seq2[string, any], span parser.Span) (any, error) {
+			return span.String(), nil
+		}}
+		parseMap := parser.BootstrapParser[any]("T", grammar, parser.WrapSpanHandler(spans))
+		when.YouErr(parseMap("abc")).Expect(t, "1:1..1:4")
+
+		plain := func(name string) parser.Converter {
+			return func(result iter.Seq2[string, any]) (any, error) {
+				return name, nil
+			}
+		}
+		parseFunc := parser.BootstrapParser[any]("T", grammar, parser.WrapHandler(plain))
+		when.YouErr(parseFunc("abc")).Expect(t, "T")
+
+		var handler parser.Handler = plain
+		when.YouErr(parser.Parse("T", grammar, handler, "abc")).Expect(t, "T")
+		var spanHandler parser.SpanHandler = parser.WrapSpanHandler(handler)
+		when.YouErr(parser.Parse("T", grammar, spanHandler, "abc")).Expect(t, "T")
+
+		var wrapped parser.Handler = parser.WrapHandler(spanHandler)
+		when.YouErr(parser.Parse("T", grammar, wrapped, "abc")).Expect(t, "T")
+	})
+}
@@ -5,25 +5,47 @@ package parser
 func PegGrammar() *Grammar {
 	grammar := NewGrammar()
 	grammar.AddRule("Grammar", Seq(Ref("Line"), Rep(Seq(Ref("EOL"), Ref("Line"))), Opt(Ref("EOL")), Ref("EOF")))
-	grammar.AddRule("Line", Alt(Ref("Rule"), Ref("Comment"), Ref("WS")))
-	grammar.AddRule("Rule", Seq(Ref("WS"), Ref("Name"), Ref("WS"), Lit(`=`), Ref("WS"), Ref("Expr"), Ref("WS")))
+	grammar.AddRule("Line", Alt(Ref("Import"), Ref("Tokens"), Ref("Ignores"), Ref("Lexicals"), Ref("Skip"), Ref("Rule"), Ref("Comment"), Ref("WS")))
+	grammar.AddRule("Import", Seq(Ref("WS"), Lit(`%import`), Ref("WS"), Alt(Ref("Path"), Ref("Name")), Opt(Seq(Ref("WS"), Lit(`as`), Ref("WS"), Ref("Alias"))), Ref("WS")))
+	grammar.AddRule("Path", Alt(Ref("SingleLit"), Ref("DoubleLit")))
+	grammar.AddRule("Alias", Ref("Name"))
+	grammar.AddRule("Tokens", Seq(Ref("WS"), Lit(`%token`), Ref("WS"), Ref("Name"), Rep(Seq(Ref("WS"), Ref("Name"))), Ref("WS")))
+	grammar.AddRule("Ignores", Seq(Ref("WS"), Lit(`%ignore`), Ref("WS"), Ref("Name"), Rep(Seq(Ref("WS"), Ref("Name"))), Ref("WS")))
+	grammar.AddRule("Lexicals", Seq(Ref("WS"), Lit(`%lexical`), Ref("WS"), Ref("Name"), Rep(Seq(Ref("WS"), Ref("Name"))), Ref("WS")))
+	grammar.AddRule("Skip", Seq(Ref("WS"), Lit(`%skip`), Ref("WS"), Lit(`=`), Ref("WS"), Ref("Expr"), Ref("WS")))
+	grammar.AddRule("Rule", Seq(Ref("WS"), Ref("Name"), Opt(Ref("Params")), Ref("WS"), Opt(Seq(Ref("Description"), Ref("WS"))), Lit(`=`), Ref("WS"), Ref("Expr"), Ref("WS")))
+	grammar.AddRule("Params", Seq(Lit(`<`), Ref("WS"), Ref("Name"), Rep(Seq(Ref("WS"), Lit(`,`), Ref("WS"), Ref("Name"))), Ref("WS"), Lit(`>`)))
+	grammar.AddRule("Description", Alt(Ref("SingleLit"), Ref("DoubleLit")))
 	grammar.AddRule("Expr", Seq(Ref("Seq"), Rep(Seq(Ref("WS"), Lit(`/`), Ref("WS"), Ref("Seq")))))
 	grammar.AddRule("Seq", Seq(Ref("Prefix"), Rep(Seq(Ref("WS"), Ref("Prefix")))))
-	grammar.AddRule("Prefix", Alt(Ref("AndExpr"), Ref("NotExpr"), Ref("Suffix")))
+	grammar.AddRule("Prefix", Alt(Ref("LabelExpr"), Ref("AndExpr"), Ref("NotExpr"), Ref("Suffix")))
+	grammar.AddRule("LabelExpr", Seq(Ref("Label"), Lit(`:`), Ref("WS"), Ref("Prefix")))
+	grammar.AddRule("Label", Ref("Name"))
 	grammar.AddRule("AndExpr", Seq(Lit(`&`), Ref("WS"), Ref("Suffix")))
 	grammar.AddRule("NotExpr", Seq(Lit(`!`), Ref("WS"), Ref("Suffix")))
-	grammar.AddRule("Suffix", Alt(Ref("OptExpr"), Ref("RepExpr"), Ref("ReqExpr"), Ref("Primary")))
+	grammar.AddRule("Suffix", Alt(Ref("OptExpr"), Ref("RepExpr"), Ref("ReqExpr"), Ref("BoundExpr"), Ref("RecoverExpr"), Ref("Primary")))
 	grammar.AddRule("OptExpr", Seq(Ref("Primary"), Ref("WS"), Lit(`?`)))
 	grammar.AddRule("RepExpr", Seq(Ref("Primary"), Ref("WS"), Lit(`*`)))
 	grammar.AddRule("ReqExpr", Seq(Ref("Primary"), Ref("WS"), Lit(`+`)))
-	grammar.AddRule("Primary", Alt(Ref("Dot"), Ref("ParExpr"), Ref("Literal"), Ref("CharClass"), Ref("Ref")))
+	grammar.AddRule("BoundExpr", Seq(Ref("Primary"), Ref("WS"), Lit(`{`), Ref("WS"), Ref("Min"), Opt(Seq(Ref("WS"), Ref("Upto"))), Ref("WS"), Lit(`}`)))
+	grammar.AddRule("Upto", Seq(Lit(`,`), Ref("WS"), Opt(Ref("Max"))))
+	grammar.AddRule("Min", Req(Cls(`[0-9]`)))
+	grammar.AddRule("Max", Req(Cls(`[0-9]`)))
+	grammar.AddRule("RecoverExpr", Seq(Ref("Primary"), Ref("WS"), Lit(`~>`), Ref("WS"), Ref("Primary")))
+	grammar.AddRule("Primary", Alt(Ref("Dot"), Ref("Cut"), Ref("ParExpr"), Ref("CaptureExpr"), Ref("BindExpr"), Ref("BackRef"), Ref("Literal"), Ref("CharClass"), Ref("Ref")))
 	grammar.AddRule("Dot", Lit(`.`))
+	grammar.AddRule("Cut", Lit(`^`))
 	grammar.AddRule("ParExpr", Seq(Lit(`(`), Ref("WS"), Ref("Expr"), Ref("WS"), Lit(`)`)))
-	grammar.AddRule("Literal", Alt(Ref("SingleLit"), Ref("DoubleLit")))
-	grammar.AddRule("CharClass", Ref("Pattern"))
-	grammar.AddRule("Ref", Ref("Name"))
+	grammar.AddRule("CaptureExpr", Seq(Lit(`$(`), Ref("WS"), Ref("Expr"), Ref("WS"), Lit(`)`)))
+	grammar.AddRule("BindExpr", Seq(Lit(`$`), Ref("Name"), Lit(`(`), Ref("WS"), Ref("Expr"), Ref("WS"), Lit(`)`)))
+	grammar.AddRule("BackRef", Seq(Lit(`$`), Ref("Name")))
+	grammar.AddRule("Literal", Seq(Alt(Ref("SingleLit"), Ref("DoubleLit")), Opt(Ref("NoCase"))))
+	grammar.AddRule("CharClass", Seq(Ref("Pattern"), Opt(Ref("NoCase"))))
+	grammar.AddRule("Ref", Seq(Ref("Name"), Opt(Ref("Args"))))
+	grammar.AddRule("Args", Seq(Lit(`<`), Ref("WS"), Ref("Expr"), Rep(Seq(Ref("WS"), Lit(`,`), Ref("WS"), Ref("Expr"))), Ref("WS"), Lit(`>`)))
 	grammar.AddRule("Comment", Seq(Lit(`#`), Rep(Seq(Not(Ref("EOL")), Dot()))))
-	grammar.AddRule("Name", Seq(Cls(`[_a-zA-Z]`), Rep(Cls(`[_a-zA-Z0-9]`))))
+	grammar.AddRule("Name", Seq(Cls(`[_a-zA-Z]`), Rep(Cls(`[_a-zA-Z0-9]`)), Rep(Seq(Lit(`.`), Cls(`[_a-zA-Z]`), Rep(Cls(`[_a-zA-Z0-9]`))))))
+	grammar.AddRule("NoCase", Seq(Lit(`i`), Not(Cls(`[_a-zA-Z0-9]`))))
 	grammar.AddRule("Pattern", Seq(Lit(`[`), Req(Alt(Lit(`\]`), Cls(`[^\]]`))), Lit(`]`)))
 	grammar.AddRule("SingleLit", Seq(Lit(`'`), Rep(Alt(Seq(Lit(`\`), Ref("SingleEscape")), Ref("SinglePlain"))), Lit(`'`)))
 	grammar.AddRule("DoubleLit", Seq(Lit(`"`), Rep(Alt(Seq(Lit(`\`), Ref("DoubleEscape")), Ref("DoublePlain"))), Lit(`"`)))
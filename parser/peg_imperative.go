@@ -0,0 +1,2808 @@
+package parser
+
+// generated by parser.Generate, do not edit
+
+import (
+	"regexp"
+)
+
+func PegImperative() *Grammar {
+	grammar, err := NewGrammar().
+		AddRule("Grammar", Gen(parsePegGrammarBody, Seq(Ref("Line"), Rep(Seq(Ref("EOL"), Ref("Line"))), Opt(Ref("EOL")), Ref("EOF")))).
+		AddRule("Line", Gen(parsePegLineBody, Alt(Ref("Import"), Ref("Tokens"), Ref("Ignores"), Ref("Lexicals"), Ref("Skip"), Ref("Rule"), Ref("Comment"), Ref("WS")))).
+		AddRule("Import", Gen(parsePegImportBody, Seq(Ref("WS"), Lit("%import"), Ref("WS"), Alt(Ref("Path"), Ref("Name")), Opt(Seq(Ref("WS"), Lit("as"), Ref("WS"), Ref("Alias"))), Ref("WS")))).
+		AddRule("Path", Gen(parsePegPathBody, Alt(Ref("SingleLit"), Ref("DoubleLit")))).
+		AddRule("Alias", Gen(parsePegAliasBody, Ref("Name"))).
+		AddRule("Tokens", Gen(parsePegTokensBody, Seq(Ref("WS"), Lit("%token"), Ref("WS"), Ref("Name"), Rep(Seq(Ref("WS"), Ref("Name"))), Ref("WS")))).
+		AddRule("Ignores", Gen(parsePegIgnoresBody, Seq(Ref("WS"), Lit("%ignore"), Ref("WS"), Ref("Name"), Rep(Seq(Ref("WS"), Ref("Name"))), Ref("WS")))).
+		AddRule("Lexicals", Gen(parsePegLexicalsBody, Seq(Ref("WS"), Lit("%lexical"), Ref("WS"), Ref("Name"), Rep(Seq(Ref("WS"), Ref("Name"))), Ref("WS")))).
+		AddRule("Skip", Gen(parsePegSkipBody, Seq(Ref("WS"), Lit("%skip"), Ref("WS"), Lit("="), Ref("WS"), Ref("Expr"), Ref("WS")))).
+		AddRule("Rule", Gen(parsePegRuleBody, Seq(Ref("WS"), Ref("Name"), Opt(Ref("Params")), Ref("WS"), Opt(Seq(Ref("Description"), Ref("WS"))), Lit("="), Ref("WS"), Ref("Expr"), Ref("WS")))).
+		AddRule("Params", Gen(parsePegParamsBody, Seq(Lit("<"), Ref("WS"), Ref("Name"), Rep(Seq(Ref("WS"), Lit(","), Ref("WS"), Ref("Name"))), Ref("WS"), Lit(">")))).
+		AddRule("Description", Gen(parsePegDescriptionBody, Alt(Ref("SingleLit"), Ref("DoubleLit")))).
+		AddRule("Expr", Gen(parsePegExprBody, Seq(Ref("Seq"), Rep(Seq(Ref("WS"), Lit("/"), Ref("WS"), Ref("Seq")))))).
+		AddRule("Seq", Gen(parsePegSeqBody, Seq(Ref("Prefix"), Rep(Seq(Ref("WS"), Ref("Prefix")))))).
+		AddRule("Prefix", Gen(parsePegPrefixBody, Alt(Ref("LabelExpr"), Ref("AndExpr"), Ref("NotExpr"), Ref("Suffix")))).
+		AddRule("LabelExpr", Gen(parsePegLabelExprBody, Seq(Ref("Label"), Lit(":"), Ref("WS"), Ref("Prefix")))).
+		AddRule("Label", Gen(parsePegLabelBody, Ref("Name"))).
+		AddRule("AndExpr", Gen(parsePegAndExprBody, Seq(Lit("&"), Ref("WS"), Ref("Suffix")))).
+		AddRule("NotExpr", Gen(parsePegNotExprBody, Seq(Lit("!"), Ref("WS"), Ref("Suffix")))).
+		AddRule("Suffix", Gen(parsePegSuffixBody, Alt(Ref("OptExpr"), Ref("RepExpr"), Ref("ReqExpr"), Ref("BoundExpr"), Ref("RecoverExpr"), Ref("Primary")))).
+		AddRule("OptExpr", Gen(parsePegOptExprBody, Seq(Ref("Primary"), Ref("WS"), Lit("?")))).
+		AddRule("RepExpr", Gen(parsePegRepExprBody, Seq(Ref("Primary"), Ref("WS"), Lit("*")))).
+		AddRule("ReqExpr", Gen(parsePegReqExprBody, Seq(Ref("Primary"), Ref("WS"), Lit("+")))).
+		AddRule("BoundExpr", Gen(parsePegBoundExprBody, Seq(Ref("Primary"), Ref("WS"), Lit("{"), Ref("WS"), Ref("Min"), Opt(Seq(Ref("WS"), Ref("Upto"))), Ref("WS"), Lit("}")))).
+		AddRule("Upto", Gen(parsePegUptoBody, Seq(Lit(","), Ref("WS"), Opt(Ref("Max"))))).
+		AddRule("Min", Gen(parsePegMinBody, Req(Cls("[0-9]")))).
+		AddRule("Max", Gen(parsePegMaxBody, Req(Cls("[0-9]")))).
+		AddRule("RecoverExpr", Gen(parsePegRecoverExprBody, Seq(Ref("Primary"), Ref("WS"), Lit("~>"), Ref("WS"), Ref("Primary")))).
+		AddRule("Primary", Gen(parsePegPrimaryBody, Alt(Ref("Dot"), Ref("Cut"), Ref("ParExpr"), Ref("CaptureExpr"), Ref("BindExpr"), Ref("BackRef"), Ref("Literal"), Ref("CharClass"), Ref("Ref")))).
+		AddRule("Dot", Gen(parsePegDotBody, Lit("."))).
+		AddRule("Cut", Gen(parsePegCutBody, Lit("^"))).
+		AddRule("ParExpr", Gen(parsePegParExprBody, Seq(Lit("("), Ref("WS"), Ref("Expr"), Ref("WS"), Lit(")")))).
+		AddRule("CaptureExpr", Gen(parsePegCaptureExprBody, Seq(Lit("$("), Ref("WS"), Ref("Expr"), Ref("WS"), Lit(")")))).
+		AddRule("BindExpr", Gen(parsePegBindExprBody, Seq(Lit("$"), Ref("Name"), Lit("("), Ref("WS"), Ref("Expr"), Ref("WS"), Lit(")")))).
+		AddRule("BackRef", Gen(parsePegBackRefBody, Seq(Lit("$"), Ref("Name")))).
+		AddRule("Literal", Gen(parsePegLiteralBody, Seq(Alt(Ref("SingleLit"), Ref("DoubleLit")), Opt(Ref("NoCase"))))).
+		AddRule("CharClass", Gen(parsePegCharClassBody, Seq(Ref("Pattern"), Opt(Ref("NoCase"))))).
+		AddRule("Ref", Gen(parsePegRefBody, Seq(Ref("Name"), Opt(Ref("Args"))))).
+		AddRule("Args", Gen(parsePegArgsBody, Seq(Lit("<"), Ref("WS"), Ref("Expr"), Rep(Seq(Ref("WS"), Lit(","), Ref("WS"), Ref("Expr"))), Ref("WS"), Lit(">")))).
+		AddRule("Comment", Gen(parsePegCommentBody, Seq(Lit("#"), Rep(Seq(Not(Ref("EOL")), Dot()))))).
+		AddRule("Name", Gen(parsePegNameBody, Seq(Cls("[_a-zA-Z]"), Rep(Cls("[_a-zA-Z0-9]")), Rep(Seq(Lit("."), Cls("[_a-zA-Z]"), Rep(Cls("[_a-zA-Z0-9]"))))))).
+		AddRule("NoCase", Gen(parsePegNoCaseBody, Seq(Lit("i"), Not(Cls("[_a-zA-Z0-9]"))))).
+		AddRule("Pattern", Gen(parsePegPatternBody, Seq(Lit("["), Req(Alt(Lit("\\]"), Cls("[^\\]]"))), Lit("]")))).
+		AddRule("SingleLit", Gen(parsePegSingleLitBody, Seq(Lit("'"), Rep(Alt(Seq(Lit("\\"), Ref("SingleEscape")), Ref("SinglePlain"))), Lit("'")))).
+		AddRule("DoubleLit", Gen(parsePegDoubleLitBody, Seq(Lit("\""), Rep(Alt(Seq(Lit("\\"), Ref("DoubleEscape")), Ref("DoublePlain"))), Lit("\"")))).
+		AddRule("SingleEscape", Gen(parsePegSingleEscapeBody, Cls("[\\\\'nrt]"))).
+		AddRule("DoubleEscape", Gen(parsePegDoubleEscapeBody, Cls("[\\\\\"nrt]"))).
+		AddRule("SinglePlain", Gen(parsePegSinglePlainBody, Req(Cls("[^\\\\']")))).
+		AddRule("DoublePlain", Gen(parsePegDoublePlainBody, Req(Cls("[^\\\\\"]")))).
+		AddRule("WS", Gen(parsePegWSBody, Rep(Cls("[ \\t]")))).
+		AddRule("EOL", Gen(parsePegEOLBody, Cls("[\\n\\r]"))).
+		AddRule("EOF", Gen(parsePegEOFBody, Not(Dot()))).
+		Compile()
+	if err != nil {
+		panic(err)
+	}
+	return grammar
+}
+
+var (
+	pegClass0 = regexp.MustCompile("[0-9]")
+	pegClass1 = regexp.MustCompile("[_a-zA-Z]")
+	pegClass2 = regexp.MustCompile("[_a-zA-Z0-9]")
+	pegClass3 = regexp.MustCompile("[^\\]]")
+	pegClass4 = regexp.MustCompile("[\\\\'nrt]")
+	pegClass5 = regexp.MustCompile("[\\\\\"nrt]")
+	pegClass6 = regexp.MustCompile("[^\\\\']")
+	pegClass7 = regexp.MustCompile("[^\\\\\"]")
+	pegClass8 = regexp.MustCompile("[ \\t]")
+	pegClass9 = regexp.MustCompile("[\\n\\r]")
+)
+
+func parsePegGrammar(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(0, parsePegGrammarBody)
+}
+
+func parsePegGrammarBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegLine(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					var result3 *ParseResult
+					res, err = parsePegEOL(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegLine(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+				end3:
+					res = result3
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark4 := c.Mark()
+			res, err = parsePegEOL(c)
+			if err != nil && !IsHard(err) {
+				c.Reset(mark4)
+				res, err = nil, nil
+			}
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegEOF(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegLine(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(1, parsePegLineBody)
+}
+
+func parsePegLineBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		mark1 := c.Mark()
+		var failure1 error
+		res, err = parsePegImport(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegTokens(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegIgnores(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegLexicals(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegSkip(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegRule(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegComment(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegWS(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = nil, failure1
+	end1:
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegImport(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(4, parsePegImportBody)
+}
+
+func parsePegImportBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if mark := c.Mark(); c.Token() == "%" && c.Next() == nil && c.Token() == "i" && c.Next() == nil && c.Token() == "m" && c.Next() == nil && c.Token() == "p" && c.Next() == nil && c.Token() == "o" && c.Next() == nil && c.Token() == "r" && c.Next() == nil && c.Token() == "t" && c.Next() == nil {
+			res, err = NewResult("", "%import"), nil
+		} else {
+			c.Reset(mark)
+			res, err = nil, c.Error("'%import'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark2 := c.Mark()
+			var failure2 error
+			res, err = parsePegPath(c)
+			if err == nil || IsHard(err) {
+				goto end2
+			}
+			failure2 = c.Backtrack(failure2, err, mark2)
+			res, err = parsePegName(c)
+			if err == nil || IsHard(err) {
+				goto end2
+			}
+			failure2 = c.Backtrack(failure2, err, mark2)
+			res, err = nil, failure2
+		end2:
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark3 := c.Mark()
+			{
+				var result4 *ParseResult
+				res, err = parsePegWS(c)
+				if err != nil {
+					goto end4
+				}
+				result4 = result4.Chain(res)
+				if mark := c.Mark(); c.Token() == "a" && c.Next() == nil && c.Token() == "s" && c.Next() == nil {
+					res, err = NewResult("", "as"), nil
+				} else {
+					c.Reset(mark)
+					res, err = nil, c.Error("'as'")
+				}
+				if err != nil {
+					goto end4
+				}
+				result4 = result4.Chain(res)
+				res, err = parsePegWS(c)
+				if err != nil {
+					goto end4
+				}
+				result4 = result4.Chain(res)
+				res, err = parsePegAlias(c)
+				if err != nil {
+					goto end4
+				}
+				result4 = result4.Chain(res)
+			end4:
+				res = result4
+			}
+			if err != nil && !IsHard(err) {
+				c.Reset(mark3)
+				res, err = nil, nil
+			}
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegPath(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(12, parsePegPathBody)
+}
+
+func parsePegPathBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		mark1 := c.Mark()
+		var failure1 error
+		res, err = parsePegSingleLit(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegDoubleLit(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = nil, failure1
+	end1:
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegAlias(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(14, parsePegAliasBody)
+}
+
+func parsePegAliasBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	res, err = parsePegName(c)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegTokens(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(5, parsePegTokensBody)
+}
+
+func parsePegTokensBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if mark := c.Mark(); c.Token() == "%" && c.Next() == nil && c.Token() == "t" && c.Next() == nil && c.Token() == "o" && c.Next() == nil && c.Token() == "k" && c.Next() == nil && c.Token() == "e" && c.Next() == nil && c.Token() == "n" && c.Next() == nil {
+			res, err = NewResult("", "%token"), nil
+		} else {
+			c.Reset(mark)
+			res, err = nil, c.Error("'%token'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegName(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					var result3 *ParseResult
+					res, err = parsePegWS(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegName(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+				end3:
+					res = result3
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegIgnores(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(6, parsePegIgnoresBody)
+}
+
+func parsePegIgnoresBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if mark := c.Mark(); c.Token() == "%" && c.Next() == nil && c.Token() == "i" && c.Next() == nil && c.Token() == "g" && c.Next() == nil && c.Token() == "n" && c.Next() == nil && c.Token() == "o" && c.Next() == nil && c.Token() == "r" && c.Next() == nil && c.Token() == "e" && c.Next() == nil {
+			res, err = NewResult("", "%ignore"), nil
+		} else {
+			c.Reset(mark)
+			res, err = nil, c.Error("'%ignore'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegName(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					var result3 *ParseResult
+					res, err = parsePegWS(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegName(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+				end3:
+					res = result3
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegLexicals(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(7, parsePegLexicalsBody)
+}
+
+func parsePegLexicalsBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if mark := c.Mark(); c.Token() == "%" && c.Next() == nil && c.Token() == "l" && c.Next() == nil && c.Token() == "e" && c.Next() == nil && c.Token() == "x" && c.Next() == nil && c.Token() == "i" && c.Next() == nil && c.Token() == "c" && c.Next() == nil && c.Token() == "a" && c.Next() == nil && c.Token() == "l" && c.Next() == nil {
+			res, err = NewResult("", "%lexical"), nil
+		} else {
+			c.Reset(mark)
+			res, err = nil, c.Error("'%lexical'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegName(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					var result3 *ParseResult
+					res, err = parsePegWS(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegName(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+				end3:
+					res = result3
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegSkip(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(8, parsePegSkipBody)
+}
+
+func parsePegSkipBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if mark := c.Mark(); c.Token() == "%" && c.Next() == nil && c.Token() == "s" && c.Next() == nil && c.Token() == "k" && c.Next() == nil && c.Token() == "i" && c.Next() == nil && c.Token() == "p" && c.Next() == nil {
+			res, err = NewResult("", "%skip"), nil
+		} else {
+			c.Reset(mark)
+			res, err = nil, c.Error("'%skip'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "=" && c.Next() == nil {
+			res, err = NewResult("", "="), nil
+		} else {
+			res, err = nil, c.Error("'='")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegExpr(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegRule(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(9, parsePegRuleBody)
+}
+
+func parsePegRuleBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegName(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark2 := c.Mark()
+			res, err = parsePegParams(c)
+			if err != nil && !IsHard(err) {
+				c.Reset(mark2)
+				res, err = nil, nil
+			}
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark3 := c.Mark()
+			{
+				var result4 *ParseResult
+				res, err = parsePegDescription(c)
+				if err != nil {
+					goto end4
+				}
+				result4 = result4.Chain(res)
+				res, err = parsePegWS(c)
+				if err != nil {
+					goto end4
+				}
+				result4 = result4.Chain(res)
+			end4:
+				res = result4
+			}
+			if err != nil && !IsHard(err) {
+				c.Reset(mark3)
+				res, err = nil, nil
+			}
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "=" && c.Next() == nil {
+			res, err = NewResult("", "="), nil
+		} else {
+			res, err = nil, c.Error("'='")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegExpr(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegParams(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(18, parsePegParamsBody)
+}
+
+func parsePegParamsBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "<" && c.Next() == nil {
+			res, err = NewResult("", "<"), nil
+		} else {
+			res, err = nil, c.Error("'<'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegName(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					var result3 *ParseResult
+					res, err = parsePegWS(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					if c.Token() == "," && c.Next() == nil {
+						res, err = NewResult("", ","), nil
+					} else {
+						res, err = nil, c.Error("','")
+					}
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegWS(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegName(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+				end3:
+					res = result3
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == ">" && c.Next() == nil {
+			res, err = NewResult("", ">"), nil
+		} else {
+			res, err = nil, c.Error("'>'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegDescription(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(19, parsePegDescriptionBody)
+}
+
+func parsePegDescriptionBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		mark1 := c.Mark()
+		var failure1 error
+		res, err = parsePegSingleLit(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegDoubleLit(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = nil, failure1
+	end1:
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(17, parsePegExprBody)
+}
+
+func parsePegExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegSeq(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					var result3 *ParseResult
+					res, err = parsePegWS(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					if c.Token() == "/" && c.Next() == nil {
+						res, err = NewResult("", "/"), nil
+					} else {
+						res, err = nil, c.Error("'/'")
+					}
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegWS(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegSeq(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+				end3:
+					res = result3
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegSeq(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(20, parsePegSeqBody)
+}
+
+func parsePegSeqBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegPrefix(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					var result3 *ParseResult
+					res, err = parsePegWS(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegPrefix(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+				end3:
+					res = result3
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegPrefix(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(21, parsePegPrefixBody)
+}
+
+func parsePegPrefixBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		mark1 := c.Mark()
+		var failure1 error
+		res, err = parsePegLabelExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegAndExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegNotExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegSuffix(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = nil, failure1
+	end1:
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegLabelExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(22, parsePegLabelExprBody)
+}
+
+func parsePegLabelExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegLabel(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == ":" && c.Next() == nil {
+			res, err = NewResult("", ":"), nil
+		} else {
+			res, err = nil, c.Error("':'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegPrefix(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegLabel(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(26, parsePegLabelBody)
+}
+
+func parsePegLabelBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	res, err = parsePegName(c)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegAndExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(23, parsePegAndExprBody)
+}
+
+func parsePegAndExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "&" && c.Next() == nil {
+			res, err = NewResult("", "&"), nil
+		} else {
+			res, err = nil, c.Error("'&'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegSuffix(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegNotExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(24, parsePegNotExprBody)
+}
+
+func parsePegNotExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "!" && c.Next() == nil {
+			res, err = NewResult("", "!"), nil
+		} else {
+			res, err = nil, c.Error("'!'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegSuffix(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegSuffix(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(25, parsePegSuffixBody)
+}
+
+func parsePegSuffixBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		mark1 := c.Mark()
+		var failure1 error
+		res, err = parsePegOptExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegRepExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegReqExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegBoundExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegRecoverExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegPrimary(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = nil, failure1
+	end1:
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegOptExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(27, parsePegOptExprBody)
+}
+
+func parsePegOptExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegPrimary(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "?" && c.Next() == nil {
+			res, err = NewResult("", "?"), nil
+		} else {
+			res, err = nil, c.Error("'?'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegRepExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(28, parsePegRepExprBody)
+}
+
+func parsePegRepExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegPrimary(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "*" && c.Next() == nil {
+			res, err = NewResult("", "*"), nil
+		} else {
+			res, err = nil, c.Error("'*'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegReqExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(29, parsePegReqExprBody)
+}
+
+func parsePegReqExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegPrimary(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "+" && c.Next() == nil {
+			res, err = NewResult("", "+"), nil
+		} else {
+			res, err = nil, c.Error("'+'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegBoundExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(30, parsePegBoundExprBody)
+}
+
+func parsePegBoundExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegPrimary(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "{" && c.Next() == nil {
+			res, err = NewResult("", "{"), nil
+		} else {
+			res, err = nil, c.Error("'{'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegMin(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark2 := c.Mark()
+			{
+				var result3 *ParseResult
+				res, err = parsePegWS(c)
+				if err != nil {
+					goto end3
+				}
+				result3 = result3.Chain(res)
+				res, err = parsePegUpto(c)
+				if err != nil {
+					goto end3
+				}
+				result3 = result3.Chain(res)
+			end3:
+				res = result3
+			}
+			if err != nil && !IsHard(err) {
+				c.Reset(mark2)
+				res, err = nil, nil
+			}
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "}" && c.Next() == nil {
+			res, err = NewResult("", "}"), nil
+		} else {
+			res, err = nil, c.Error("'}'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegUpto(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(34, parsePegUptoBody)
+}
+
+func parsePegUptoBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "," && c.Next() == nil {
+			res, err = NewResult("", ","), nil
+		} else {
+			res, err = nil, c.Error("','")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark2 := c.Mark()
+			res, err = parsePegMax(c)
+			if err != nil && !IsHard(err) {
+				c.Reset(mark2)
+				res, err = nil, nil
+			}
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegMin(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(33, parsePegMinBody)
+}
+
+func parsePegMinBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		for count1 := 0; ; count1++ {
+			mark1 := c.Mark()
+			if token := c.Token(); pegClass0.MatchString(token) {
+				res, err = NewResult("", token), c.Next()
+			} else {
+				res, err = nil, c.Error("[0-9]")
+			}
+			if err != nil && (count1 == 0 || IsHard(err)) {
+				break
+			}
+			if err != nil || count1 > 0 && c.At(mark1) {
+				c.Reset(mark1)
+				err = nil
+				break
+			}
+			result1 = result1.Chain(res)
+		}
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegMax(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(35, parsePegMaxBody)
+}
+
+func parsePegMaxBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		for count1 := 0; ; count1++ {
+			mark1 := c.Mark()
+			if token := c.Token(); pegClass0.MatchString(token) {
+				res, err = NewResult("", token), c.Next()
+			} else {
+				res, err = nil, c.Error("[0-9]")
+			}
+			if err != nil && (count1 == 0 || IsHard(err)) {
+				break
+			}
+			if err != nil || count1 > 0 && c.At(mark1) {
+				c.Reset(mark1)
+				err = nil
+				break
+			}
+			result1 = result1.Chain(res)
+		}
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegRecoverExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(31, parsePegRecoverExprBody)
+}
+
+func parsePegRecoverExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegPrimary(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if mark := c.Mark(); c.Token() == "~" && c.Next() == nil && c.Token() == ">" && c.Next() == nil {
+			res, err = NewResult("", "~>"), nil
+		} else {
+			c.Reset(mark)
+			res, err = nil, c.Error("'~>'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegPrimary(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegPrimary(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(32, parsePegPrimaryBody)
+}
+
+func parsePegPrimaryBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		mark1 := c.Mark()
+		var failure1 error
+		res, err = parsePegDot(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegCut(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegParExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegCaptureExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegBindExpr(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegBackRef(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegLiteral(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegCharClass(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parsePegRef(c)
+		if err == nil || IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = nil, failure1
+	end1:
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegDot(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(36, parsePegDotBody)
+}
+
+func parsePegDotBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	if c.Token() == "." && c.Next() == nil {
+		res, err = NewResult("", "."), nil
+	} else {
+		res, err = nil, c.Error("'.'")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegCut(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(37, parsePegCutBody)
+}
+
+func parsePegCutBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	if c.Token() == "^" && c.Next() == nil {
+		res, err = NewResult("", "^"), nil
+	} else {
+		res, err = nil, c.Error("'^'")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegParExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(38, parsePegParExprBody)
+}
+
+func parsePegParExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "(" && c.Next() == nil {
+			res, err = NewResult("", "("), nil
+		} else {
+			res, err = nil, c.Error("'('")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegExpr(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == ")" && c.Next() == nil {
+			res, err = NewResult("", ")"), nil
+		} else {
+			res, err = nil, c.Error("')'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegCaptureExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(39, parsePegCaptureExprBody)
+}
+
+func parsePegCaptureExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if mark := c.Mark(); c.Token() == "$" && c.Next() == nil && c.Token() == "(" && c.Next() == nil {
+			res, err = NewResult("", "$("), nil
+		} else {
+			c.Reset(mark)
+			res, err = nil, c.Error("'$('")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegExpr(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == ")" && c.Next() == nil {
+			res, err = NewResult("", ")"), nil
+		} else {
+			res, err = nil, c.Error("')'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegBindExpr(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(40, parsePegBindExprBody)
+}
+
+func parsePegBindExprBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "$" && c.Next() == nil {
+			res, err = NewResult("", "$"), nil
+		} else {
+			res, err = nil, c.Error("'$'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegName(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "(" && c.Next() == nil {
+			res, err = NewResult("", "("), nil
+		} else {
+			res, err = nil, c.Error("'('")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegExpr(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == ")" && c.Next() == nil {
+			res, err = NewResult("", ")"), nil
+		} else {
+			res, err = nil, c.Error("')'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegBackRef(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(41, parsePegBackRefBody)
+}
+
+func parsePegBackRefBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "$" && c.Next() == nil {
+			res, err = NewResult("", "$"), nil
+		} else {
+			res, err = nil, c.Error("'$'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegName(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegLiteral(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(42, parsePegLiteralBody)
+}
+
+func parsePegLiteralBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		{
+			mark2 := c.Mark()
+			var failure2 error
+			res, err = parsePegSingleLit(c)
+			if err == nil || IsHard(err) {
+				goto end2
+			}
+			failure2 = c.Backtrack(failure2, err, mark2)
+			res, err = parsePegDoubleLit(c)
+			if err == nil || IsHard(err) {
+				goto end2
+			}
+			failure2 = c.Backtrack(failure2, err, mark2)
+			res, err = nil, failure2
+		end2:
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark3 := c.Mark()
+			res, err = parsePegNoCase(c)
+			if err != nil && !IsHard(err) {
+				c.Reset(mark3)
+				res, err = nil, nil
+			}
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegCharClass(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(43, parsePegCharClassBody)
+}
+
+func parsePegCharClassBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegPattern(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark2 := c.Mark()
+			res, err = parsePegNoCase(c)
+			if err != nil && !IsHard(err) {
+				c.Reset(mark2)
+				res, err = nil, nil
+			}
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegRef(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(44, parsePegRefBody)
+}
+
+func parsePegRefBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		res, err = parsePegName(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark2 := c.Mark()
+			res, err = parsePegArgs(c)
+			if err != nil && !IsHard(err) {
+				c.Reset(mark2)
+				res, err = nil, nil
+			}
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegArgs(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(47, parsePegArgsBody)
+}
+
+func parsePegArgsBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "<" && c.Next() == nil {
+			res, err = NewResult("", "<"), nil
+		} else {
+			res, err = nil, c.Error("'<'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegExpr(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					var result3 *ParseResult
+					res, err = parsePegWS(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					if c.Token() == "," && c.Next() == nil {
+						res, err = NewResult("", ","), nil
+					} else {
+						res, err = nil, c.Error("','")
+					}
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegWS(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = parsePegExpr(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+				end3:
+					res = result3
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = parsePegWS(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == ">" && c.Next() == nil {
+			res, err = NewResult("", ">"), nil
+		} else {
+			res, err = nil, c.Error("'>'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegComment(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(10, parsePegCommentBody)
+}
+
+func parsePegCommentBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "#" && c.Next() == nil {
+			res, err = NewResult("", "#"), nil
+		} else {
+			res, err = nil, c.Error("'#'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					var result3 *ParseResult
+					{
+						mark4 := c.Mark()
+						c.Quiet(true)
+						res, err = parsePegEOL(c)
+						c.Quiet(false)
+						c.Reset(mark4)
+						if err == nil {
+							err = c.Error("not Ref(\"EOL\")")
+						} else if !IsHard(err) {
+							err = nil
+						}
+						res = nil
+					}
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					res, err = NewResult("", c.Token()), c.Next()
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+				end3:
+					res = result3
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegName(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(13, parsePegNameBody)
+}
+
+func parsePegNameBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if token := c.Token(); pegClass1.MatchString(token) {
+			res, err = NewResult("", token), c.Next()
+		} else {
+			res, err = nil, c.Error("[_a-zA-Z]")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				if token := c.Token(); pegClass2.MatchString(token) {
+					res, err = NewResult("", token), c.Next()
+				} else {
+					res, err = nil, c.Error("[_a-zA-Z0-9]")
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result3 *ParseResult
+			for {
+				mark3 := c.Mark()
+				{
+					var result4 *ParseResult
+					if c.Token() == "." && c.Next() == nil {
+						res, err = NewResult("", "."), nil
+					} else {
+						res, err = nil, c.Error("'.'")
+					}
+					if err != nil {
+						goto end4
+					}
+					result4 = result4.Chain(res)
+					if token := c.Token(); pegClass1.MatchString(token) {
+						res, err = NewResult("", token), c.Next()
+					} else {
+						res, err = nil, c.Error("[_a-zA-Z]")
+					}
+					if err != nil {
+						goto end4
+					}
+					result4 = result4.Chain(res)
+					{
+						var result5 *ParseResult
+						for {
+							mark5 := c.Mark()
+							if token := c.Token(); pegClass2.MatchString(token) {
+								res, err = NewResult("", token), c.Next()
+							} else {
+								res, err = nil, c.Error("[_a-zA-Z0-9]")
+							}
+							if IsHard(err) {
+								break
+							}
+							if err != nil || c.At(mark5) {
+								c.Reset(mark5)
+								err = nil
+								break
+							}
+							result5 = result5.Chain(res)
+						}
+						res = result5
+					}
+					if err != nil {
+						goto end4
+					}
+					result4 = result4.Chain(res)
+				end4:
+					res = result4
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark3) {
+					c.Reset(mark3)
+					err = nil
+					break
+				}
+				result3 = result3.Chain(res)
+			}
+			res = result3
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegNoCase(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(45, parsePegNoCaseBody)
+}
+
+func parsePegNoCaseBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "i" && c.Next() == nil {
+			res, err = NewResult("", "i"), nil
+		} else {
+			res, err = nil, c.Error("'i'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark2 := c.Mark()
+			c.Quiet(true)
+			if token := c.Token(); pegClass2.MatchString(token) {
+				res, err = NewResult("", token), c.Next()
+			} else {
+				res, err = nil, c.Error("[_a-zA-Z0-9]")
+			}
+			c.Quiet(false)
+			c.Reset(mark2)
+			if err == nil {
+				err = c.Error("not Cls(\"[_a-zA-Z0-9]\")")
+			} else if !IsHard(err) {
+				err = nil
+			}
+			res = nil
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegPattern(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(46, parsePegPatternBody)
+}
+
+func parsePegPatternBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "[" && c.Next() == nil {
+			res, err = NewResult("", "["), nil
+		} else {
+			res, err = nil, c.Error("'['")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for count2 := 0; ; count2++ {
+				mark2 := c.Mark()
+				{
+					mark3 := c.Mark()
+					var failure3 error
+					if mark := c.Mark(); c.Token() == "\\" && c.Next() == nil && c.Token() == "]" && c.Next() == nil {
+						res, err = NewResult("", "\\]"), nil
+					} else {
+						c.Reset(mark)
+						res, err = nil, c.Error("'\\\\]'")
+					}
+					if err == nil || IsHard(err) {
+						goto end3
+					}
+					failure3 = c.Backtrack(failure3, err, mark3)
+					if token := c.Token(); pegClass3.MatchString(token) {
+						res, err = NewResult("", token), c.Next()
+					} else {
+						res, err = nil, c.Error("[^\\]]")
+					}
+					if err == nil || IsHard(err) {
+						goto end3
+					}
+					failure3 = c.Backtrack(failure3, err, mark3)
+					res, err = nil, failure3
+				end3:
+				}
+				if err != nil && (count2 == 0 || IsHard(err)) {
+					break
+				}
+				if err != nil || count2 > 0 && c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "]" && c.Next() == nil {
+			res, err = NewResult("", "]"), nil
+		} else {
+			res, err = nil, c.Error("']'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegSingleLit(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(15, parsePegSingleLitBody)
+}
+
+func parsePegSingleLitBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "'" && c.Next() == nil {
+			res, err = NewResult("", "'"), nil
+		} else {
+			res, err = nil, c.Error("'\\''")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					mark3 := c.Mark()
+					var failure3 error
+					{
+						var result4 *ParseResult
+						if c.Token() == "\\" && c.Next() == nil {
+							res, err = NewResult("", "\\"), nil
+						} else {
+							res, err = nil, c.Error("'\\\\'")
+						}
+						if err != nil {
+							goto end4
+						}
+						result4 = result4.Chain(res)
+						res, err = parsePegSingleEscape(c)
+						if err != nil {
+							goto end4
+						}
+						result4 = result4.Chain(res)
+					end4:
+						res = result4
+					}
+					if err == nil || IsHard(err) {
+						goto end3
+					}
+					failure3 = c.Backtrack(failure3, err, mark3)
+					res, err = parsePegSinglePlain(c)
+					if err == nil || IsHard(err) {
+						goto end3
+					}
+					failure3 = c.Backtrack(failure3, err, mark3)
+					res, err = nil, failure3
+				end3:
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "'" && c.Next() == nil {
+			res, err = NewResult("", "'"), nil
+		} else {
+			res, err = nil, c.Error("'\\''")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegDoubleLit(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(16, parsePegDoubleLitBody)
+}
+
+func parsePegDoubleLitBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		if c.Token() == "\"" && c.Next() == nil {
+			res, err = NewResult("", "\""), nil
+		} else {
+			res, err = nil, c.Error("'\"'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					mark3 := c.Mark()
+					var failure3 error
+					{
+						var result4 *ParseResult
+						if c.Token() == "\\" && c.Next() == nil {
+							res, err = NewResult("", "\\"), nil
+						} else {
+							res, err = nil, c.Error("'\\\\'")
+						}
+						if err != nil {
+							goto end4
+						}
+						result4 = result4.Chain(res)
+						res, err = parsePegDoubleEscape(c)
+						if err != nil {
+							goto end4
+						}
+						result4 = result4.Chain(res)
+					end4:
+						res = result4
+					}
+					if err == nil || IsHard(err) {
+						goto end3
+					}
+					failure3 = c.Backtrack(failure3, err, mark3)
+					res, err = parsePegDoublePlain(c)
+					if err == nil || IsHard(err) {
+						goto end3
+					}
+					failure3 = c.Backtrack(failure3, err, mark3)
+					res, err = nil, failure3
+				end3:
+				}
+				if IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "\"" && c.Next() == nil {
+			res, err = NewResult("", "\""), nil
+		} else {
+			res, err = nil, c.Error("'\"'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegSingleEscape(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(48, parsePegSingleEscapeBody)
+}
+
+func parsePegSingleEscapeBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	if token := c.Token(); pegClass4.MatchString(token) {
+		res, err = NewResult("", token), c.Next()
+	} else {
+		res, err = nil, c.Error("[\\\\'nrt]")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegDoubleEscape(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(50, parsePegDoubleEscapeBody)
+}
+
+func parsePegDoubleEscapeBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	if token := c.Token(); pegClass5.MatchString(token) {
+		res, err = NewResult("", token), c.Next()
+	} else {
+		res, err = nil, c.Error("[\\\\\"nrt]")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegSinglePlain(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(49, parsePegSinglePlainBody)
+}
+
+func parsePegSinglePlainBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		for count1 := 0; ; count1++ {
+			mark1 := c.Mark()
+			if token := c.Token(); pegClass6.MatchString(token) {
+				res, err = NewResult("", token), c.Next()
+			} else {
+				res, err = nil, c.Error("[^\\\\']")
+			}
+			if err != nil && (count1 == 0 || IsHard(err)) {
+				break
+			}
+			if err != nil || count1 > 0 && c.At(mark1) {
+				c.Reset(mark1)
+				err = nil
+				break
+			}
+			result1 = result1.Chain(res)
+		}
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegDoublePlain(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(51, parsePegDoublePlainBody)
+}
+
+func parsePegDoublePlainBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		for count1 := 0; ; count1++ {
+			mark1 := c.Mark()
+			if token := c.Token(); pegClass7.MatchString(token) {
+				res, err = NewResult("", token), c.Next()
+			} else {
+				res, err = nil, c.Error("[^\\\\\"]")
+			}
+			if err != nil && (count1 == 0 || IsHard(err)) {
+				break
+			}
+			if err != nil || count1 > 0 && c.At(mark1) {
+				c.Reset(mark1)
+				err = nil
+				break
+			}
+			result1 = result1.Chain(res)
+		}
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegWS(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(11, parsePegWSBody)
+}
+
+func parsePegWSBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		var result1 *ParseResult
+		for {
+			mark1 := c.Mark()
+			if token := c.Token(); pegClass8.MatchString(token) {
+				res, err = NewResult("", token), c.Next()
+			} else {
+				res, err = nil, c.Error("[ \\t]")
+			}
+			if IsHard(err) {
+				break
+			}
+			if err != nil || c.At(mark1) {
+				c.Reset(mark1)
+				err = nil
+				break
+			}
+			result1 = result1.Chain(res)
+		}
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegEOL(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(2, parsePegEOLBody)
+}
+
+func parsePegEOLBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	if token := c.Token(); pegClass9.MatchString(token) {
+		res, err = NewResult("", token), c.Next()
+	} else {
+		res, err = nil, c.Error("[\\n\\r]")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parsePegEOF(c *ParseContext) (*ParseResult, error) {
+	return c.Apply(3, parsePegEOFBody)
+}
+
+func parsePegEOFBody(c *ParseContext) (*ParseResult, error) {
+	var res *ParseResult
+	var err error
+	{
+		mark1 := c.Mark()
+		c.Quiet(true)
+		res, err = NewResult("", c.Token()), c.Next()
+		c.Quiet(false)
+		c.Reset(mark1)
+		if err == nil {
+			err = c.Error("end of input")
+		} else if !IsHard(err) {
+			err = nil
+		}
+		res = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
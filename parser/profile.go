@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+RuleProfile holds the statistics gathered for a single rule. Time is the
+cumulative time spent evaluating the rule, including the rules it references,
+so recursive rules count nested evaluations more than once.
+*/
+type RuleProfile struct {
+	Name        string        `json:"name"`
+	Invocations int           `json:"invocations"`
+	MemoHits    int           `json:"memoHits"`
+	MemoMisses  int           `json:"memoMisses"`
+	Backtracks  int           `json:"backtracks"`
+	Reseeds     int           `json:"reseeds"`
+	Time        time.Duration `json:"time"`
+}
+
+/*
+Profile collects per-rule statistics over a parse.
+*/
+type Profile struct {
+	rules map[string]*RuleProfile
+}
+
+/*
+Creates an empty profile, to be filled in by Parse with WithProfile.
+*/
+func NewProfile() *Profile {
+	return &Profile{make(map[string]*RuleProfile)}
+}
+
+func (p *Profile) rule(name string) *RuleProfile {
+	stats, ok := p.rules[name]
+	if !ok {
+		stats = &RuleProfile{Name: name}
+		p.rules[name] = stats
+	}
+	return stats
+}
+
+/*
+Returns the statistics for a rule, or nil if the rule was never invoked.
+*/
+func (p *Profile) Rule(name string) *RuleProfile {
+	return p.rules[name]
+}
+
+/*
+Returns the statistics for every invoked rule, most time consuming first.
+*/
+func (p *Profile) Rules() []*RuleProfile {
+	rules := make([]*RuleProfile, 0, len(p.rules))
+	for _, stats := range p.rules {
+		rules = append(rules, stats)
+	}
+	slices.SortFunc(rules, func(a, b *RuleProfile) int {
+		return cmp.Or(cmp.Compare(b.Time, a.Time), strings.Compare(a.Name, b.Name))
+	})
+	return rules
+}
+
+func (p *Profile) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-20s %11s %9s %9s %10s %7s %12s\n", "rule", "invocations", "hits", "misses", "backtracks", "reseeds", "time")
+	for _, stats := range p.Rules() {
+		fmt.Fprintf(&sb, "%-20s %11d %9d %9d %10d %7d %12s\n", stats.Name, stats.Invocations, stats.MemoHits, stats.MemoMisses, stats.Backtracks, stats.Reseeds, stats.Time)
+	}
+	return sb.String()
+}
+
+/*
+Encodes the profile as a JSON array of rule statistics, most time consuming first.
+*/
+func (p *Profile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Rules())
+}
@@ -0,0 +1,35 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+func TestProfile(t *testing.T) {
+	t.Run("Profile", func(t *testing.T) {
+		grammar := parser.NewGrammar()
+		grammar.AddRule("S", parser.Alt(parser.Seq(parser.Ref("L"), parser.Ref("T"), parser.Lit("z")), parser.Seq(parser.Ref("L"), parser.Ref("T"))))
+		grammar.AddRule("L", parser.Alt(parser.Seq(parser.Ref("L"), parser.Lit("a")), parser.Lit("a")))
+		grammar.AddRule("T", parser.Alt(parser.Lit("x"), parser.Lit("b")))
+		profile := parser.NewProfile()
+		result, err := parser.Parse("S", grammar, parser.WrapHandler(nil), "aaab", parser.WithProfile(profile))
+		when.YouErr(result, err).Expect(t, "aaab")
+
+		s := profile.Rule("S")
+		when.You([]int{s.Invocations, s.MemoHits, s.MemoMisses, s.Backtracks, s.Reseeds}).Expect(t, []int{1, 0, 1, 1, 0})
+		l := profile.Rule("L")
+		when.You([]int{l.Invocations, l.MemoHits, l.MemoMisses, l.Backtracks, l.Reseeds}).Expect(t, []int{6, 5, 1, 2, 3})
+		tr := profile.Rule("T")
+		when.You([]int{tr.Invocations, tr.MemoHits, tr.MemoMisses, tr.Backtracks, tr.Reseeds}).Expect(t, []int{2, 1, 1, 1, 0})
+		when.You(profile.Rule("U")).Expect(t, nil)
+
+		encoded := when.YouErr(json.Marshal(profile)).ExpectSuccess(t)
+		var decoded []map[string]any
+		when.You(json.Unmarshal(encoded, &decoded)).Expect(t, nil)
+		when.You(len(decoded)).Expect(t, 3)
+		when.You(decoded[0]["name"]).Expect(t, "S")
+	})
+}
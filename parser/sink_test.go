@@ -0,0 +1,558 @@
+package parser_test
+
+// generated by parser.Generate, do not edit
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fuwjax/gopase/parser"
+)
+
+func SinkImperative() *parser.Grammar {
+	grammar, err := parser.NewGrammar().
+		AddRule("S", parser.Gen(parseSinkSBody, parser.Seq(parser.Ref("Item"), parser.Rep(parser.Seq(parser.Lit(","), parser.Cut(), parser.Ref("Item"))), parser.Not(parser.Dot())))).
+		AddRule("Item", parser.Gen(parseSinkItemBody, parser.Alt(parser.Ref("Pair"), parser.Ref("Word"), parser.Ref("Num"), parser.Ref("Heredoc"), parser.Ref("Folded"), parser.Ref("Bad")))).
+		AddRule("Pair", parser.Gen(parseSinkPairBody, parser.Seq(parser.Label("key", parser.Ref("Word")), parser.Lit("="), parser.Label("value", parser.Alt(parser.Ref("Num"), parser.Ref("Word")))))).
+		AddRule("Word", parser.Gen(parseSinkWordBody, parser.Capture(parser.Req(parser.Cls("[a-z]"))))).
+		AddRule("Num", parser.Gen(parseSinkNumBody, parser.Seq(parser.Bound(parser.Cls("[0-9]"), 1, 3), parser.See(parser.Cls("[^0-9]"))))).
+		AddRule("Heredoc", parser.Gen(parseSinkHeredocBody, parser.Seq(parser.Lit("<"), parser.Bind("tag", parser.Req(parser.Cls("[A-Z]"))), parser.Lit(">"), parser.Rep(parser.Seq(parser.Not(parser.BackRef("tag")), parser.Dot())), parser.BackRef("tag")))).
+		Add(parser.NewRule("Folded", parser.Gen(parseSinkFoldedBody, parser.Alt(parser.LitI("yes"), parser.Seq(parser.See(parser.Lit("n")), parser.Lit("no"))))).Describe("yes or no")).
+		AddRule("Bad", parser.Gen(parseSinkBadBody, parser.Recover(parser.Lit("?"), parser.Lit(",")))).
+		Compile()
+	if err != nil {
+		panic(err)
+	}
+	return grammar
+}
+
+var (
+	sinkClass0 = regexp.MustCompile("[a-z]")
+	sinkClass1 = regexp.MustCompile("[0-9]")
+	sinkClass2 = regexp.MustCompile("[^0-9]")
+	sinkClass3 = regexp.MustCompile("[A-Z]")
+)
+
+func parseSinkS(c *parser.ParseContext) (*parser.ParseResult, error) {
+	return c.Apply(0, parseSinkSBody)
+}
+
+func parseSinkSBody(c *parser.ParseContext) (*parser.ParseResult, error) {
+	var res *parser.ParseResult
+	var err error
+	{
+		var result1 *parser.ParseResult
+		res, err = parseSinkItem(c)
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result2 *parser.ParseResult
+			for {
+				mark2 := c.Mark()
+				{
+					var result3 *parser.ParseResult
+					cut3 := false
+					if c.Token() == "," && c.Next() == nil {
+						res, err = parser.NewResult("", ","), nil
+					} else {
+						res, err = nil, c.Error("','")
+					}
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+					c.Cut()
+					cut3 = true
+					res, err = parseSinkItem(c)
+					if err != nil {
+						goto end3
+					}
+					result3 = result3.Chain(res)
+				end3:
+					if err != nil && cut3 {
+						err = c.Commit(err)
+					}
+					res = result3
+				}
+				if parser.IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark4 := c.Mark()
+			c.Quiet(true)
+			res, err = parser.NewResult("", c.Token()), c.Next()
+			c.Quiet(false)
+			c.Reset(mark4)
+			if err == nil {
+				err = c.Error("end of input")
+			} else if !parser.IsHard(err) {
+				err = nil
+			}
+			res = nil
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseSinkItem(c *parser.ParseContext) (*parser.ParseResult, error) {
+	return c.Apply(1, parseSinkItemBody)
+}
+
+func parseSinkItemBody(c *parser.ParseContext) (*parser.ParseResult, error) {
+	var res *parser.ParseResult
+	var err error
+	{
+		mark1 := c.Mark()
+		var failure1 error
+		res, err = parseSinkPair(c)
+		if err == nil || parser.IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parseSinkWord(c)
+		if err == nil || parser.IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parseSinkNum(c)
+		if err == nil || parser.IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parseSinkHeredoc(c)
+		if err == nil || parser.IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parseSinkFolded(c)
+		if err == nil || parser.IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = parseSinkBad(c)
+		if err == nil || parser.IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = nil, failure1
+	end1:
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseSinkPair(c *parser.ParseContext) (*parser.ParseResult, error) {
+	return c.Apply(2, parseSinkPairBody)
+}
+
+func parseSinkPairBody(c *parser.ParseContext) (*parser.ParseResult, error) {
+	var res *parser.ParseResult
+	var err error
+	{
+		var result1 *parser.ParseResult
+		res, err = parseSinkWord(c)
+		if err == nil {
+			res = res.Label("key")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == "=" && c.Next() == nil {
+			res, err = parser.NewResult("", "="), nil
+		} else {
+			res, err = nil, c.Error("'='")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark2 := c.Mark()
+			var failure2 error
+			res, err = parseSinkNum(c)
+			if err == nil || parser.IsHard(err) {
+				goto end2
+			}
+			failure2 = c.Backtrack(failure2, err, mark2)
+			res, err = parseSinkWord(c)
+			if err == nil || parser.IsHard(err) {
+				goto end2
+			}
+			failure2 = c.Backtrack(failure2, err, mark2)
+			res, err = nil, failure2
+		end2:
+		}
+		if err == nil {
+			res = res.Label("value")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseSinkWord(c *parser.ParseContext) (*parser.ParseResult, error) {
+	return c.Apply(3, parseSinkWordBody)
+}
+
+func parseSinkWordBody(c *parser.ParseContext) (*parser.ParseResult, error) {
+	var res *parser.ParseResult
+	var err error
+	{
+		mark1 := c.Mark()
+		{
+			var result2 *parser.ParseResult
+			for count2 := 0; ; count2++ {
+				mark2 := c.Mark()
+				if token := c.Token(); sinkClass0.MatchString(token) {
+					res, err = parser.NewResult("", token), c.Next()
+				} else {
+					res, err = nil, c.Error("[a-z]")
+				}
+				if err != nil && (count2 == 0 || parser.IsHard(err)) {
+					break
+				}
+				if err != nil || count2 > 0 && c.At(mark2) {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+			}
+			res = result2
+		}
+		if err == nil {
+			res = c.Captured(mark1, res)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseSinkNum(c *parser.ParseContext) (*parser.ParseResult, error) {
+	return c.Apply(4, parseSinkNumBody)
+}
+
+func parseSinkNumBody(c *parser.ParseContext) (*parser.ParseResult, error) {
+	var res *parser.ParseResult
+	var err error
+	{
+		var result1 *parser.ParseResult
+		{
+			start2 := c.Mark()
+			var result2 *parser.ParseResult
+			for count2 := 0; count2 < 3; count2++ {
+				mark2 := c.Mark()
+				if token := c.Token(); sinkClass1.MatchString(token) {
+					res, err = parser.NewResult("", token), c.Next()
+				} else {
+					res, err = nil, c.Error("[0-9]")
+				}
+				if parser.IsHard(err) {
+					break
+				}
+				if err != nil && count2 < 1 {
+					c.Reset(start2)
+					break
+				}
+				if err != nil {
+					c.Reset(mark2)
+					err = nil
+					break
+				}
+				result2 = result2.Chain(res)
+				if c.At(mark2) {
+					break
+				}
+			}
+			res = result2
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark3 := c.Mark()
+			c.Quiet(true)
+			if token := c.Token(); sinkClass2.MatchString(token) {
+				res, err = parser.NewResult("", token), c.Next()
+			} else {
+				res, err = nil, c.Error("[^0-9]")
+			}
+			c.Quiet(false)
+			c.Reset(mark3)
+			res = nil
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseSinkHeredoc(c *parser.ParseContext) (*parser.ParseResult, error) {
+	return c.Apply(5, parseSinkHeredocBody)
+}
+
+func parseSinkHeredocBody(c *parser.ParseContext) (*parser.ParseResult, error) {
+	var res *parser.ParseResult
+	var err error
+	{
+		var result1 *parser.ParseResult
+		if c.Token() == "<" && c.Next() == nil {
+			res, err = parser.NewResult("", "<"), nil
+		} else {
+			res, err = nil, c.Error("'<'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			mark2 := c.Mark()
+			{
+				var result3 *parser.ParseResult
+				for count3 := 0; ; count3++ {
+					mark3 := c.Mark()
+					if token := c.Token(); sinkClass3.MatchString(token) {
+						res, err = parser.NewResult("", token), c.Next()
+					} else {
+						res, err = nil, c.Error("[A-Z]")
+					}
+					if err != nil && (count3 == 0 || parser.IsHard(err)) {
+						break
+					}
+					if err != nil || count3 > 0 && c.At(mark3) {
+						c.Reset(mark3)
+						err = nil
+						break
+					}
+					result3 = result3.Chain(res)
+				}
+				res = result3
+			}
+			if err == nil {
+				c.Bind("tag", mark2)
+			}
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		if c.Token() == ">" && c.Next() == nil {
+			res, err = parser.NewResult("", ">"), nil
+		} else {
+			res, err = nil, c.Error("'>'")
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		{
+			var result4 *parser.ParseResult
+			for {
+				mark4 := c.Mark()
+				{
+					var result5 *parser.ParseResult
+					{
+						mark6 := c.Mark()
+						c.Quiet(true)
+						res, err = c.BackRef("tag")
+						c.Quiet(false)
+						c.Reset(mark6)
+						if err == nil {
+							err = c.Error("not BackRef(\"tag\")")
+						} else if !parser.IsHard(err) {
+							err = nil
+						}
+						res = nil
+					}
+					if err != nil {
+						goto end5
+					}
+					result5 = result5.Chain(res)
+					res, err = parser.NewResult("", c.Token()), c.Next()
+					if err != nil {
+						goto end5
+					}
+					result5 = result5.Chain(res)
+				end5:
+					res = result5
+				}
+				if parser.IsHard(err) {
+					break
+				}
+				if err != nil || c.At(mark4) {
+					c.Reset(mark4)
+					err = nil
+					break
+				}
+				result4 = result4.Chain(res)
+			}
+			res = result4
+		}
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+		res, err = c.BackRef("tag")
+		if err != nil {
+			goto end1
+		}
+		result1 = result1.Chain(res)
+	end1:
+		res = result1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseSinkFolded(c *parser.ParseContext) (*parser.ParseResult, error) {
+	return c.Apply(6, parseSinkFoldedBody)
+}
+
+func parseSinkFoldedBody(c *parser.ParseContext) (*parser.ParseResult, error) {
+	var res *parser.ParseResult
+	var err error
+	{
+		mark1 := c.Mark()
+		var failure1 error
+		if mark := c.Mark(); strings.EqualFold(c.Token(), "y") && c.Next() == nil && strings.EqualFold(c.Token(), "e") && c.Next() == nil && strings.EqualFold(c.Token(), "s") && c.Next() == nil {
+			res, err = parser.NewResult("", c.Substring(mark)), nil
+		} else {
+			c.Reset(mark)
+			res, err = nil, c.Error("'yes'i")
+		}
+		if err == nil || parser.IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		{
+			var result2 *parser.ParseResult
+			{
+				mark3 := c.Mark()
+				c.Quiet(true)
+				if c.Token() == "n" && c.Next() == nil {
+					res, err = parser.NewResult("", "n"), nil
+				} else {
+					res, err = nil, c.Error("'n'")
+				}
+				c.Quiet(false)
+				c.Reset(mark3)
+				res = nil
+			}
+			if err != nil {
+				goto end2
+			}
+			result2 = result2.Chain(res)
+			if mark := c.Mark(); c.Token() == "n" && c.Next() == nil && c.Token() == "o" && c.Next() == nil {
+				res, err = parser.NewResult("", "no"), nil
+			} else {
+				c.Reset(mark)
+				res, err = nil, c.Error("'no'")
+			}
+			if err != nil {
+				goto end2
+			}
+			result2 = result2.Chain(res)
+		end2:
+			res = result2
+		}
+		if err == nil || parser.IsHard(err) {
+			goto end1
+		}
+		failure1 = c.Backtrack(failure1, err, mark1)
+		res, err = nil, failure1
+	end1:
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseSinkBad(c *parser.ParseContext) (*parser.ParseResult, error) {
+	return c.Apply(7, parseSinkBadBody)
+}
+
+func parseSinkBadBody(c *parser.ParseContext) (*parser.ParseResult, error) {
+	var res *parser.ParseResult
+	var err error
+	res, err = c.Recover(parseSinkBad_1, parseSinkBad_2)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseSinkBad_1(c *parser.ParseContext) (*parser.ParseResult, error) {
+	var res *parser.ParseResult
+	var err error
+	if c.Token() == "?" && c.Next() == nil {
+		res, err = parser.NewResult("", "?"), nil
+	} else {
+		res, err = nil, c.Error("'?'")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseSinkBad_2(c *parser.ParseContext) (*parser.ParseResult, error) {
+	var res *parser.ParseResult
+	var err error
+	if c.Token() == "," && c.Next() == nil {
+		res, err = parser.NewResult("", ","), nil
+	} else {
+		res, err = nil, c.Error("','")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
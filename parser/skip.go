@@ -0,0 +1,92 @@
+package parser
+
+/*
+Sets the expression skipped before every literal, character class, . and rule
+reference of a syntactic rule, repeated until it stops matching, so a rule's
+span starts after what was skipped. Rules marked Lexical, and
+every rule they use, match their input exactly. A grammar with token rules
+skips nothing; its lexer already drops what it ignores.
+*/
+func (g *Grammar) Skip(expr Expr) *Grammar {
+	g.skip = expr
+	return g
+}
+
+/*
+Returns the expression set by Skip, or nil.
+*/
+func (g *Grammar) SkipExpr() Expr {
+	return g.skip
+}
+
+/*
+Marks the rules as lexical, so nothing is skipped while parsing them.
+*/
+func (g *Grammar) Lexical(names ...string) *Grammar {
+	return g.mark(exact, names)
+}
+
+/*
+Returns the names of the rules marked Lexical, in order.
+*/
+func (g *Grammar) Lexicals() []string {
+	return g.lexicalNames(exact)
+}
+
+/*
+Returns the rules that match their input exactly: the Lexical rules, the rules
+used by the skip expression, and every rule they use. Nil if the grammar skips
+nothing.
+*/
+func (g *Grammar) exactRules() map[string]bool {
+	if g.skip == nil || g.lexed() {
+		return nil
+	}
+	names := g.Lexicals()
+	walk(g.skip, func(expr Expr) {
+		if ref, ok := expr.(*Reference); ok {
+			names = append(names, ref.name)
+		}
+	})
+	rules := make(map[string]bool)
+	for _, name := range names {
+		for used := range g.reachable(name) {
+			rules[used] = true
+		}
+	}
+	return rules
+}
+
+/*
+A %skip directive.
+*/
+type skipDirective struct {
+	expr Expr
+}
+
+/*
+Skips whatever the grammar skips at the current position, unless inside an
+exact rule.
+*/
+func (c *ParseContext) skip() error {
+	if c.grammar.skip == nil || c.exact > 0 || c.lexed() {
+		return nil
+	}
+	c.exact++
+	c.quiet++
+	defer func() {
+		c.exact--
+		c.quiet--
+	}()
+	for {
+		mark := c.Mark()
+		_, err := c.grammar.skip.Parse(c)
+		if IsHard(err) {
+			return err
+		}
+		if err != nil || c.At(mark) {
+			c.Reset(mark)
+			return nil
+		}
+	}
+}
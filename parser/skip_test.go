@@ -0,0 +1,49 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+const skippedJson = `
+%skip = [ \t\r\n] / Comment
+%lexical Number String
+Doc = Value !.
+Value = Object / Array / Number / String / 'null'
+Object = '{' (Member (',' Member)*)? '}'
+Member = String ':' Value
+Array = '[' (Value (',' Value)*)? ']'
+Number = '-'? [0-9]+
+String = '"' [^"]* '"'
+Comment = '#' [^\n]*
+`
+
+func TestSkip(t *testing.T) {
+	grammar := when.YouErr(parser.Bootstrap(skippedJson)).ExpectSuccess(t)
+	when.You(grammar.Lexicals()).Expect(t, []string{"Number", "String"})
+	when.You(grammar.SkipExpr()).Expect(t, parser.Alt(parser.Cls(`[ \t\r\n]`), parser.Ref("Comment")))
+	when.You(grammar.Validate()).Expect(t, parser.Problems(nil))
+
+	parse := parser.NewParser[string]("Doc", skippedJson, nil)
+	when.YouErr(parse("{ \"a b\" : [ 1 , -2 ] # note\n }\n")).Expect(t, `{"a b":[1,-2]}`)
+	when.YouErr(parse("[1 2]")).ExpectError(t, "at 1:4 expected one of ',', ']'\nwhile in Array\nwhile in Value\nwhile in Doc")
+	when.YouErr(parse("[- 2]")).ExpectError(t, "at 1:3 expected [0-9]\nwhile in Number\nwhile in Value\nwhile in Array\nwhile in Value\nwhile in Doc")
+}
+
+func TestSkipWithoutLexical(t *testing.T) {
+	parse := parser.NewParser[string]("Sum", "%skip = ' '\nSum = [0-9] ('+' [0-9])*", nil)
+	when.YouErr(parse(" 1 + 2+3 ")).Expect(t, "1+2+3")
+}
+
+func TestSkipBeforeRule(t *testing.T) {
+	grammar := when.YouErr(parser.Bootstrap("%skip = ' '\nS = A A\nA = 'a'")).ExpectSuccess(t)
+	tree := when.YouErr(parser.ParseTree("S", grammar, "a   a")).ExpectSuccess(t)
+	when.You(tree.Children[1].Span).Expect(t, parser.Span{1, 5, 4, 1, 6, 5})
+	when.You(tree.Children[1].Text).Expect(t, "a")
+
+	capture := parser.NewParser[string]("S", "%skip = ' '\nS = A $(A)\nA = 'a'", nil)
+	when.YouErr(capture("a   a")).Expect(t, "aa")
+	when.YouErr(parser.NewProgramParser[string]("S", "%skip = ' '\nS = A $(A)\nA = 'a'", nil)("a   a")).Expect(t, "aa")
+}
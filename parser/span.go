@@ -0,0 +1,40 @@
+package parser
+
+import "fmt"
+
+/*
+Span is the region of input between two positions. Lines and columns are
+1-based, offsets are byte offsets into the input. The end is exclusive.
+*/
+type Span struct {
+	StartLine, StartColumn, StartOffset int
+	EndLine, EndColumn, EndOffset       int
+}
+
+/*
+Creates the Span from start up to end.
+*/
+func newSpan(start, end *ParsePosition) Span {
+	return Span{start.Line(), start.Column(), start.Offset(), end.Line(), end.Column(), end.Offset()}
+}
+
+func (s Span) String() string {
+	return fmt.Sprintf("%d:%d..%d:%d", s.StartLine, s.StartColumn, s.EndLine, s.EndColumn)
+}
+
+/*
+Captured is the result of a Capture expression: the exact text it matched and
+where.
+*/
+type Captured struct {
+	Text string
+	Span Span
+}
+
+/*
+Returns the captured text, so rules without a handler concatenate captures as
+plain text.
+*/
+func (c Captured) String() string {
+	return c.Text
+}
@@ -0,0 +1,221 @@
+package parser
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fuwjax/gopase/funki"
+)
+
+/*
+Uses types to generate a handler. Each type handles the rule of the same name,
+given by an example value like Rule{} or &Rule{}; the rule then returns a value
+of that type. Qualified rules like json.String are handled by the type for the
+unqualified name.
+
+A struct field tagged peg:"Name" is set from the results of the Name reference,
+the first one, or every one for a slice field. A tag may list several names,
+like peg:"Object,Array". An untagged field of type Span is set to the span of
+the rule. Any other type is set from the text the rule matched, parsing numbers
+and booleans as needed. Integers are decimal, so a leading zero is just a zero.
+
+A rule without a type passes a lone result through, so a rule of alternatives
+can fill an interface field; otherwise it returns the text it matched.
+*/
+func StructHandler(types ...any) *TypeHandler {
+	byName := make(map[string]reflect.Type)
+	for _, t := range types {
+		typ := reflect.TypeOf(t)
+		name := typ.Name()
+		if typ.Kind() == reflect.Pointer {
+			name = typ.Elem().Name()
+		}
+		byName[name] = typ
+	}
+	return &TypeHandler{byName}
+}
+
+/*
+TypeHandler is the handler made by StructHandler.
+*/
+type TypeHandler struct {
+	types map[string]reflect.Type
+}
+
+/*
+Returns the names of the rules handled by a type, in order.
+*/
+func (h *TypeHandler) Names() []string {
+	return slices.Sorted(maps.Keys(h.types))
+}
+
+/*
+Returns the converter for the rule, which passes a lone result through if the
+rule has no type.
+*/
+func (h *TypeHandler) Converter(name string) SpanConverter {
+	typ, ok := h.types[name[strings.LastIndex(name, ".")+1:]]
+	if !ok {
+		return passThrough
+	}
+	return func(results iter.Seq2[string, any], span Span) (any, error) {
+		value := reflect.New(typ).Elem()
+		target := value
+		if typ.Kind() == reflect.Pointer {
+			value.Set(reflect.New(typ.Elem()))
+			target = value.Elem()
+		}
+		if target.Kind() != reflect.Struct {
+			err := assign(target, concat(results))
+			return value.Interface(), err
+		}
+		for i := range target.NumField() {
+			field := target.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			tag, tagged := field.Tag.Lookup("peg")
+			if !tagged {
+				if field.Type == spanType {
+					target.Field(i).Set(reflect.ValueOf(span))
+				}
+				continue
+			}
+			if err := fill(target.Field(i), results, strings.Split(tag, ",")); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", target.Type().Name(), field.Name, err)
+			}
+		}
+		return value.Interface(), nil
+	}
+}
+
+/*
+Returns a lone result as is, or the concatenated results.
+*/
+func passThrough(results iter.Seq2[string, any], _ Span) (any, error) {
+	var values []any
+	for name, value := range results {
+		if name != ErrorKey {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	return concat(results), nil
+}
+
+/*
+Returns the results as text, as a rule without a converter does.
+*/
+func concat(results iter.Seq2[string, any]) string {
+	var sb strings.Builder
+	for name, value := range results {
+		if name != ErrorKey {
+			writeText(&sb, value)
+		}
+	}
+	return sb.String()
+}
+
+/*
+Writes a result as text. A label on an unmatched expression holds nil, which
+writes nothing, and a label on several results holds all of them.
+*/
+func writeText(sb *strings.Builder, value any) {
+	switch v := value.(type) {
+	case nil:
+	case []any:
+		for _, item := range v {
+			writeText(sb, item)
+		}
+	default:
+		sb.WriteString(fmt.Sprint(v))
+	}
+}
+
+/*
+Sets a field from the results for the names, appending each one to a slice
+field.
+*/
+func fill(field reflect.Value, results iter.Seq2[string, any], names []string) error {
+	if field.Kind() != reflect.Slice {
+		_, value := funki.FirstOf(results, names...)
+		return assign(field, value)
+	}
+	for _, value := range funki.ListOf[any](results, names...) {
+		elem := reflect.New(field.Type().Elem()).Elem()
+		if err := assign(elem, value); err != nil {
+			return err
+		}
+		field.Set(reflect.Append(field, elem))
+	}
+	return nil
+}
+
+/*
+Sets the target to the value, taking or following a pointer, converting
+between types of the same kind, and parsing text into numbers and booleans.
+A nil value leaves the target unset.
+*/
+func assign(target reflect.Value, value any) error {
+	if value == nil {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	typ := target.Type()
+	switch {
+	case v.Type().AssignableTo(typ):
+		target.Set(v)
+		return nil
+	case v.Kind() == reflect.Pointer && v.Type().Elem().AssignableTo(typ):
+		if !v.IsNil() {
+			target.Set(v.Elem())
+		}
+		return nil
+	case v.Kind() == typ.Kind() && v.CanConvert(typ):
+		target.Set(v.Convert(typ))
+		return nil
+	}
+	if typ.Kind() == reflect.Pointer {
+		elem := reflect.New(typ.Elem())
+		if err := assign(elem.Elem(), value); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("cannot assign %T to %s", value, typ)
+	}
+	var err error
+	switch typ.Kind() {
+	case reflect.String:
+		target.SetString(text)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		target.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(text, 10, typ.Bits())
+		target.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(text, 10, typ.Bits())
+		target.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(text, typ.Bits())
+		target.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot assign %T to %s", value, typ)
+	}
+	return err
+}
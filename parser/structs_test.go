@@ -0,0 +1,74 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+type Decl struct {
+	Name  string `peg:"Name"`
+	Value Value  `peg:"Value"`
+	Span  parser.Span
+}
+
+type Value any
+
+type List struct {
+	Items []Value `peg:"Value"`
+}
+
+type Num struct {
+	Int int `peg:"Digits"`
+}
+
+type Word string
+
+type Flag struct {
+	On *bool `peg:"Bool"`
+}
+
+const structGrammar = `
+Decl = Name ' = ' Value
+Value = List / Num / Word / Flag
+List = '[' (Value (',' Value)*)? ']'
+Num = Digits
+Word = [a-z]+
+Flag = '!' Bool
+Bool = 'true' / 'false'
+Digits = [0-9]+
+Name = [a-z]+
+`
+
+func TestStructHandler(t *testing.T) {
+	handler := parser.StructHandler(&Decl{}, List{}, Num{}, Word(""), Flag{})
+	parse := parser.NewParser[*Decl]("Decl", structGrammar, handler)
+	t.Run("fields", func(t *testing.T) {
+		when.YouErr(parse("x = 42")).Expect(t, &Decl{"x", Num{42}, parser.Span{1, 1, 0, 1, 7, 6}})
+	})
+	t.Run("slice", func(t *testing.T) {
+		when.YouErr(parse("x = [1,ab,[]]")).Expect(t, &Decl{"x", List{[]Value{Num{1}, Word("ab"), List{}}}, parser.Span{1, 1, 0, 1, 14, 13}})
+	})
+	t.Run("pointer", func(t *testing.T) {
+		on := true
+		when.YouErr(parse("x = !true")).Expect(t, &Decl{"x", Flag{&on}, parser.Span{1, 1, 0, 1, 10, 9}})
+	})
+	t.Run("leading zeros", func(t *testing.T) {
+		when.YouErr(parse("x = 08")).Expect(t, &Decl{"x", Num{8}, parser.Span{1, 1, 0, 1, 7, 6}})
+		when.YouErr(parse("x = 010")).Expect(t, &Decl{"x", Num{10}, parser.Span{1, 1, 0, 1, 8, 7}})
+	})
+	t.Run("bad conversion", func(t *testing.T) {
+		parse := parser.NewParser[Num]("Num", "Num = Digits\nDigits = [a-z]+", parser.StructHandler(Num{}))
+		when.YouErr(parse("abc")).ExpectError(t, "Num.Int: strconv.ParseInt: parsing \"abc\": invalid syntax\nwhile in Num")
+	})
+}
+
+func TestStructHandlerTags(t *testing.T) {
+	type Pair struct {
+		Keys  []string `peg:"Key,Other"`
+		First string   `peg:"Other,Key"`
+	}
+	parse := parser.NewParser[Pair]("Pair", "Pair = Key ':' Other ':' Key\nKey = [a-z]+\nOther = [0-9]+", parser.StructHandler(Pair{}))
+	when.YouErr(parse("ab:12:cd")).Expect(t, Pair{[]string{"ab", "12", "cd"}, "ab"})
+}
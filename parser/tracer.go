@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*
+Tracer receives the steps of a parse as they happen. Enter and Exit bracket
+the evaluation of a rule, Match and Fail report terminal expressions, and
+MemoHit reports a rule result served from the packrat cache. Failures inside
+lookaheads are reported like any other.
+*/
+type Tracer interface {
+	Enter(rule string, at *ParsePosition)
+	Exit(rule string, at, end *ParsePosition, err error)
+	Match(expr Expr, at, end *ParsePosition)
+	Fail(expr Expr, at *ParsePosition, err error)
+	MemoHit(rule string, at *ParsePosition)
+}
+
+/*
+Creates a Tracer that writes one indented line per step to out.
+*/
+func NewTextTracer(out io.Writer) Tracer {
+	return &textTracer{out, 0}
+}
+
+type textTracer struct {
+	out   io.Writer
+	depth int
+}
+
+func (t *textTracer) printf(format string, args ...any) {
+	fmt.Fprintf(t.out, strings.Repeat("  ", t.depth)+format+"\n", args...)
+}
+
+func (t *textTracer) Enter(rule string, at *ParsePosition) {
+	t.printf("%s @%s {", rule, at)
+	t.depth++
+}
+
+func (t *textTracer) Exit(rule string, at, end *ParsePosition, err error) {
+	t.depth--
+	if err != nil {
+		t.printf("} %s failed @%s", rule, at)
+	} else {
+		t.printf("} %s matched @%s..%s", rule, at, end)
+	}
+}
+
+func (t *textTracer) Match(expr Expr, at, end *ParsePosition) {
+	t.printf("%s matched @%s..%s", expr, at, end)
+}
+
+func (t *textTracer) Fail(expr Expr, at *ParsePosition, err error) {
+	t.printf("%s failed @%s", expr, at)
+}
+
+func (t *textTracer) MemoHit(rule string, at *ParsePosition) {
+	t.printf("%s memo @%s", rule, at)
+}
@@ -0,0 +1,30 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+func TestTextTracer(t *testing.T) {
+	t.Run("Text Tracer", func(t *testing.T) {
+		grammar := parser.NewGrammar()
+		grammar.AddRule("S", parser.Alt(parser.Seq(parser.Ref("T"), parser.Ref("T")), parser.Seq(parser.Ref("T"), parser.Cls("[c]"))))
+		grammar.AddRule("T", parser.Lit("ab"))
+		var sb strings.Builder
+		when.YouErr(parser.Parse("S", grammar, parser.WrapHandler(nil), "abc", parser.WithTracer(parser.NewTextTracer(&sb)))).Expect(t, "abc")
+		when.You(sb.String()).Expect(t, `S @1:1 {
+  T @1:1 {
+    Lit(`+"`ab`"+`) matched @1:1..1:3
+  } T matched @1:1..1:3
+  T @1:3 {
+    Lit(`+"`ab`"+`) failed @1:3
+  } T failed @1:3
+  T memo @1:1
+  Cls("[c]") matched @1:3..1:4
+} S matched @1:1..1:4
+`)
+	})
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+/*
+Node is one rule matched by a parse with the TreeHandler. Children are the
+nodes of the rules it referenced, in order. Text is everything the rule
+matched, less anything skipped or ignored.
+*/
+type Node struct {
+	Rule     string  `json:"rule"`
+	Text     string  `json:"text"`
+	Span     Span    `json:"span"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+/*
+TreeHandler turns every rule into a Node, so a grammar can be explored before
+writing a handler for it.
+*/
+var TreeHandler SpanHandler = func(name string) SpanConverter {
+	return func(results iter.Seq2[string, any], span Span) (any, error) {
+		node := &Node{name, "", span, nil}
+		var sb strings.Builder
+		for _, value := range results {
+			node.add(&sb, value)
+		}
+		node.Text = sb.String()
+		return node, nil
+	}
+}
+
+/*
+Adds a result to this node's children and text. The results of a label on
+several expressions are added one by one.
+*/
+func (n *Node) add(sb *strings.Builder, value any) {
+	switch v := value.(type) {
+	case *Node:
+		n.Children = append(n.Children, v)
+		sb.WriteString(v.Text)
+	case *ErrorNode:
+		sb.WriteString(v.Text)
+	case []any:
+		for _, item := range v {
+			n.add(sb, item)
+		}
+	case nil:
+	default:
+		sb.WriteString(fmt.Sprint(v))
+	}
+}
+
+/*
+Parses the input into a Node tree.
+*/
+func ParseTree(root string, grammar *Grammar, input string) (*Node, error) {
+	result, err := Parse(root, grammar, TreeHandler, input)
+	if err != nil {
+		return nil, err
+	}
+	return result.(*Node), nil
+}
+
+/*
+Iterates over this node and every node below it, depth first, parents before
+their children.
+*/
+func (n *Node) Walk() iter.Seq[*Node] {
+	return func(yield func(*Node) bool) {
+		n.walk(yield)
+	}
+}
+
+func (n *Node) walk(yield func(*Node) bool) bool {
+	if !yield(n) {
+		return false
+	}
+	for _, child := range n.Children {
+		if !child.walk(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Returns the tree as an S-expression. A node without children shows its text,
+like (Number "12"); any other node shows its children, like (Sum (Number "1")
+(Number "2")).
+*/
+func (n *Node) String() string {
+	var sb strings.Builder
+	n.write(&sb)
+	return sb.String()
+}
+
+func (n *Node) write(sb *strings.Builder) {
+	sb.WriteString("(")
+	sb.WriteString(n.Rule)
+	if len(n.Children) == 0 {
+		sb.WriteString(" ")
+		sb.WriteString(strconv.Quote(n.Text))
+	}
+	for _, child := range n.Children {
+		sb.WriteString(" ")
+		child.write(sb)
+	}
+	sb.WriteString(")")
+}
@@ -0,0 +1,55 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+func sumGrammar(t *testing.T) *parser.Grammar {
+	return when.YouErr(parser.Bootstrap("Sum = Number (WS '+' WS Number)*\nNumber = [0-9]+\nWS = ' '*")).ExpectSuccess(t)
+}
+
+func TestTreeHandler(t *testing.T) {
+	tree := when.YouErr(parser.ParseTree("Sum", sumGrammar(t), "1 + 23")).ExpectSuccess(t)
+	when.You(tree.Text).Expect(t, "1 + 23")
+	when.You(tree.Span).Expect(t, parser.Span{1, 1, 0, 1, 7, 6})
+	when.You(tree.String()).Expect(t, `(Sum (Number "1") (WS " ") (WS " ") (Number "23"))`)
+
+	var rules []string
+	for node := range tree.Walk() {
+		rules = append(rules, node.Rule)
+	}
+	when.You(rules).Expect(t, []string{"Sum", "Number", "WS", "WS", "Number"})
+
+	numbers := slices.Collect(func(yield func(string) bool) {
+		for node := range tree.Walk() {
+			if node.Rule == "Number" && !yield(node.Text) {
+				return
+			}
+		}
+	})
+	when.You(numbers).Expect(t, []string{"1", "23"})
+}
+
+func TestTreeHandlerJson(t *testing.T) {
+	tree := when.YouErr(parser.ParseTree("Number", sumGrammar(t), "42")).ExpectSuccess(t)
+	when.YouErr(json.Marshal(tree)).ExpectMatch(t, func(t *testing.T, actual []byte) bool {
+		return when.AssertTrue(t, string(actual) == `{"rule":"Number","text":"42","span":{"StartLine":1,"StartColumn":1,"StartOffset":0,"EndLine":1,"EndColumn":3,"EndOffset":2}}`)
+	})
+}
+
+func TestTreeHandlerParser(t *testing.T) {
+	parse := parser.NewParser[*parser.Node]("Pair", "Pair = Key ':' Key\nKey = [a-z]+", parser.TreeHandler)
+	tree := when.YouErr(parse("ab:c")).ExpectSuccess(t)
+	when.You(tree.String()).Expect(t, `(Pair (Key "ab") (Key "c"))`)
+	when.You(tree.Text).Expect(t, "ab:c")
+
+	labeled := parser.NewParser[*parser.Node]("Pair", "Pair = k:(Key ':' Key) v:'!'?\nKey = [a-z]+", parser.TreeHandler)
+	tree = when.YouErr(labeled("ab:c")).ExpectSuccess(t)
+	when.You(tree.String()).Expect(t, `(Pair (Key "ab") (Key "c"))`)
+	when.You(tree.Text).Expect(t, "ab:c")
+}
@@ -0,0 +1,563 @@
+package parser
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/fuwjax/gopase/funki"
+)
+
+/*
+Identifies the sort of Problem found by Grammar.Validate.
+*/
+type ProblemKind int
+
+const (
+	UndefinedReference ProblemKind = iota
+	DuplicateRule
+	UnusedRule
+	EmptyRepetition
+	LeftRecursion
+	ShadowedAlternative
+	ArgumentMismatch
+	UnmatchedHandler
+	UnhandledRule
+	BadSignature
+	InvalidBound
+)
+
+func (k ProblemKind) String() string {
+	switch k {
+	case UndefinedReference:
+		return "undefined reference"
+	case DuplicateRule:
+		return "duplicate rule"
+	case UnusedRule:
+		return "unused rule"
+	case EmptyRepetition:
+		return "empty repetition"
+	case LeftRecursion:
+		return "left recursion"
+	case ShadowedAlternative:
+		return "shadowed alternative"
+	case ArgumentMismatch:
+		return "argument mismatch"
+	case UnmatchedHandler:
+		return "unmatched handler"
+	case UnhandledRule:
+		return "unhandled rule"
+	case BadSignature:
+		return "bad signature"
+	case InvalidBound:
+		return "invalid bound"
+	}
+	return fmt.Sprintf("ProblemKind(%d)", int(k))
+}
+
+/*
+Returns true for problems that keep a grammar from parsing correctly. The
+others are legal but likely mistakes; left recursion is supported by the
+engine, repetitions already stop when they make no progress, and a rule without
+a converter returns the text it matched.
+*/
+func (k ProblemKind) Fatal() bool {
+	return k == UndefinedReference || k == DuplicateRule || k == ArgumentMismatch || k == UnmatchedHandler || k == BadSignature || k == InvalidBound
+}
+
+/*
+Problem is a single finding from Grammar.Validate.
+*/
+type Problem struct {
+	Kind   ProblemKind
+	Rule   string
+	Detail string
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s in %s: %s", p.Kind, p.Rule, p.Detail)
+}
+
+/*
+Problems is the set of findings from Grammar.Validate.
+*/
+type Problems []Problem
+
+func (ps Problems) Error() string {
+	return strings.Join(funki.Apply(ps, Problem.Error), "\n")
+}
+
+/*
+Returns the fatal problems as an error, or nil if there are none.
+*/
+func (ps Problems) Err() error {
+	var fatal Problems
+	for _, p := range ps {
+		if p.Kind.Fatal() {
+			fatal = append(fatal, p)
+		}
+	}
+	if fatal == nil {
+		return nil
+	}
+	return fatal
+}
+
+/*
+Statically checks the grammar for undefined references, unused rules,
+duplicate rules, repetitions of expressions that can match empty, left
+recursion, and alternatives shadowed by an earlier literal or class. The first
+rule is taken to be the root when looking for unused rules; imported rules are
+never reported as unused.
+*/
+func (g *Grammar) Validate() Problems {
+	var problems Problems
+	seen := make(map[string]bool)
+	for _, name := range g.order {
+		if seen[name] {
+			problems = append(problems, Problem{DuplicateRule, name, "defined more than once"})
+		}
+		seen[name] = true
+	}
+	nullable := g.nullable()
+	for _, name := range g.ruleNames() {
+		rule := g.rules[name]
+		walk(rule.expr, func(expr Expr) {
+			switch x := expr.(type) {
+			case *Reference:
+				target := g.rules[x.name]
+				if target == nil {
+					target = builtins[x.name]
+				}
+				switch {
+				case target == nil && (len(x.args) > 0 || !slices.Contains(rule.params, x.name)):
+					problems = append(problems, Problem{UndefinedReference, name, "no such rule: " + x.name})
+				case target != nil && len(x.args) != len(target.params):
+					problems = append(problems, Problem{ArgumentMismatch, name, fmt.Sprintf("%s takes %d arguments, got %d", x.name, len(target.params), len(x.args))})
+				}
+			case *Repeated:
+				if isNullable(x.expr, nullable) {
+					problems = append(problems, Problem{EmptyRepetition, name, x.String() + " repeats an expression that can match empty"})
+				}
+			case *Required:
+				if isNullable(x.expr, nullable) {
+					problems = append(problems, Problem{EmptyRepetition, name, x.String() + " repeats an expression that can match empty"})
+				}
+			case *Bounded:
+				if err := x.check(); err != nil {
+					problems = append(problems, Problem{InvalidBound, name, x.String() + ": " + err.Error()})
+				}
+				if x.max != 1 && isNullable(x.expr, nullable) {
+					problems = append(problems, Problem{EmptyRepetition, name, x.String() + " repeats an expression that can match empty"})
+				}
+			case *Options:
+				for i, earlier := range x.exprs {
+					for _, later := range x.exprs[i+1:] {
+						if shadows(earlier, later) {
+							problems = append(problems, Problem{ShadowedAlternative, name, later.String() + " is shadowed by " + earlier.String()})
+						}
+					}
+				}
+			}
+		})
+	}
+	if len(g.order) > 0 {
+		reachable := g.reachable(g.order[0])
+		if g.skip != nil {
+			walk(g.skip, func(expr Expr) {
+				if ref, ok := expr.(*Reference); ok {
+					maps.Copy(reachable, g.reachable(ref.name))
+				}
+			})
+		}
+		for _, name := range g.ruleNames() {
+			if !reachable[name] && !g.imported[name] && g.rules[name].lexical == syntactic {
+				problems = append(problems, Problem{UnusedRule, name, "not reachable from " + g.order[0]})
+			}
+		}
+	}
+	for _, name := range g.ruleNames() {
+		if path := g.leftCycle(name, nullable); path != nil {
+			problems = append(problems, Problem{LeftRecursion, name, strings.Join(path, " -> ")})
+		}
+	}
+	return problems
+}
+
+var resultsType = reflect.TypeFor[iter.Seq2[string, any]]()
+
+/*
+Checks a handler, as accepted by WrapSpanHandler, against the grammar. Reports
+rules with no converter, and for method, map and StructHandler handlers,
+converters with no rule. Methods named after a rule or taking the results
+first are converters, and are reported if not shaped like a Converter or
+SpanConverter; other methods, like String, are ignored. A method or type
+handles a qualified rule like json.String by its unqualified name.
+*/
+func (g *Grammar) CheckHandler(handler any) Problems {
+	var problems Problems
+	names := make(map[string]bool)
+	for _, name := range g.ruleNames() {
+		names[name] = true
+	}
+	unqualified := func() {
+		for name := range names {
+			names[name[strings.LastIndex(name, ".")+1:]] = true
+		}
+	}
+	var converters []string
+	switch h := handler.(type) {
+	case nil, Handler, SpanHandler, func(string) SpanConverter, func(string) Converter:
+	case map[string]Converter:
+		converters = slices.Sorted(maps.Keys(h))
+	case map[string]SpanConverter:
+		converters = slices.Sorted(maps.Keys(h))
+	case *TypeHandler:
+		unqualified()
+		converters = h.Names()
+	default:
+		unqualified()
+		value := reflect.ValueOf(handler)
+		for i := range value.NumMethod() {
+			method := value.Type().Method(i)
+			signature := value.Method(i).Type()
+			if !names[method.Name] && (signature.NumIn() == 0 || signature.In(0) != resultsType) {
+				continue
+			}
+			converters = append(converters, method.Name)
+			if !isConverter(signature) {
+				problems = append(problems, Problem{BadSignature, method.Name, fmt.Sprintf("%s is not a Converter or SpanConverter", method.Type)})
+			}
+		}
+	}
+	for _, name := range converters {
+		if !names[name] {
+			problems = append(problems, Problem{UnmatchedHandler, name, "no rule named " + name})
+		}
+	}
+	wrapped := WrapSpanHandler(handler)
+	for _, name := range g.ruleNames() {
+		if wrapped(name) == nil {
+			problems = append(problems, Problem{UnhandledRule, name, "no converter, matches as text"})
+		}
+	}
+	return problems
+}
+
+/*
+Returns true if the method type, without its receiver, takes the results and
+optionally a Span, and returns a value and an error.
+*/
+func isConverter(method reflect.Type) bool {
+	in := method.NumIn()
+	return (in == 1 || in == 2 && method.In(1) == spanType) && method.In(0) == resultsType &&
+		method.NumOut() == 2 && method.Out(0) == reflect.TypeFor[any]() && method.Out(1) == reflect.TypeFor[error]()
+}
+
+/*
+Returns the rule names in order, without duplicates.
+*/
+func (g *Grammar) ruleNames() []string {
+	var names []string
+	for _, name := range g.order {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+/*
+Returns the direct sub-expressions of an expression.
+*/
+func children(expr Expr) []Expr {
+	switch x := expr.(type) {
+	case *Sequence:
+		return x.exprs
+	case *Options:
+		return x.exprs
+	case *Optional:
+		return []Expr{x.expr}
+	case *Repeated:
+		return []Expr{x.expr}
+	case *Required:
+		return []Expr{x.expr}
+	case *Recovery:
+		return []Expr{x.expr, x.sync}
+	case *PositiveLookahead:
+		return []Expr{x.expr}
+	case *NegativeLookahead:
+		return []Expr{x.expr}
+	case *Reference:
+		return x.args
+	case *Labeled:
+		return []Expr{x.expr}
+	case *Capturing:
+		return []Expr{x.expr}
+	case *Binding:
+		return []Expr{x.expr}
+	case *Bounded:
+		return []Expr{x.expr}
+	case *Generated:
+		return []Expr{x.source}
+	}
+	return nil
+}
+
+/*
+Returns a copy of the expression with its direct sub-expressions replaced.
+*/
+func withChildren(expr Expr, exprs []Expr) Expr {
+	switch x := expr.(type) {
+	case *Sequence:
+		return &Sequence{exprs}
+	case *Options:
+		return &Options{exprs}
+	case *Optional:
+		return &Optional{exprs[0]}
+	case *Repeated:
+		return &Repeated{exprs[0]}
+	case *Required:
+		return &Required{exprs[0]}
+	case *Recovery:
+		return &Recovery{exprs[0], exprs[1]}
+	case *PositiveLookahead:
+		return &PositiveLookahead{exprs[0]}
+	case *NegativeLookahead:
+		return &NegativeLookahead{exprs[0]}
+	case *Reference:
+		return &Reference{x.name, exprs, x.resolved}
+	case *Labeled:
+		return &Labeled{x.label, exprs[0]}
+	case *Capturing:
+		return &Capturing{exprs[0]}
+	case *Binding:
+		return &Binding{x.name, exprs[0]}
+	case *Bounded:
+		return &Bounded{exprs[0], x.min, x.max}
+	case *Generated:
+		return &Generated{x.parse, exprs[0]}
+	}
+	return expr
+}
+
+/*
+Rebuilds the expression bottom up, replacing every sub-expression with the
+result of fn.
+*/
+func transform(expr Expr, fn func(Expr) Expr) Expr {
+	exprs := children(expr)
+	if exprs != nil {
+		expr = withChildren(expr, funki.Apply(exprs, func(child Expr) Expr {
+			return transform(child, fn)
+		}))
+	}
+	return fn(expr)
+}
+
+/*
+Calls visit on the expression and every sub-expression.
+*/
+func walk(expr Expr, visit func(Expr)) {
+	visit(expr)
+	for _, child := range children(expr) {
+		walk(child, visit)
+	}
+}
+
+/*
+Returns the set of rules that can match without consuming input.
+*/
+func (g *Grammar) nullable() map[string]bool {
+	nullable := make(map[string]bool)
+	for name, rule := range builtins {
+		nullable[name] = g.rules[name] == nil && isNullable(rule.expr, nullable)
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, rule := range g.rules {
+			if !nullable[name] && isNullable(rule.expr, nullable) {
+				nullable[name] = true
+				changed = true
+			}
+		}
+	}
+	return nullable
+}
+
+/*
+Returns true if the expression can match without consuming input, given the
+set of nullable rules.
+*/
+func isNullable(expr Expr, nullable map[string]bool) bool {
+	switch x := expr.(type) {
+	case *Sequence:
+		for _, child := range x.exprs {
+			if !isNullable(child, nullable) {
+				return false
+			}
+		}
+		return true
+	case *Options:
+		for _, child := range x.exprs {
+			if isNullable(child, nullable) {
+				return true
+			}
+		}
+		return false
+	case *Required:
+		return isNullable(x.expr, nullable)
+	case *Recovery:
+		return isNullable(x.expr, nullable)
+	case *Labeled:
+		return isNullable(x.expr, nullable)
+	case *Capturing:
+		return isNullable(x.expr, nullable)
+	case *Binding:
+		return isNullable(x.expr, nullable)
+	case *Generated:
+		return isNullable(x.source, nullable)
+	case *Bounded:
+		return x.min == 0 || isNullable(x.expr, nullable)
+	case *Optional, *Repeated, *PositiveLookahead, *NegativeLookahead, *Commit, *BackReference:
+		return true
+	case *Indentation:
+		return x.delta <= 0
+	case *Literal:
+		return x.literal == ""
+	case *FoldedLiteral:
+		return x.literal == ""
+	case *Reference:
+		return nullable[x.name]
+	}
+	return false
+}
+
+/*
+Returns the rules that may be referenced before any input is consumed.
+*/
+func leftRefs(expr Expr, nullable map[string]bool) []string {
+	switch x := expr.(type) {
+	case *Reference:
+		return []string{x.name}
+	case *Sequence:
+		var refs []string
+		for _, child := range x.exprs {
+			refs = append(refs, leftRefs(child, nullable)...)
+			if !isNullable(child, nullable) {
+				break
+			}
+		}
+		return refs
+	}
+	var refs []string
+	for _, child := range children(expr) {
+		refs = append(refs, leftRefs(child, nullable)...)
+	}
+	return refs
+}
+
+/*
+Returns a path of left-most references from the rule back to itself, or nil
+if the rule is not left-recursive.
+*/
+func (g *Grammar) leftCycle(name string, nullable map[string]bool) []string {
+	visited := make(map[string]bool)
+	var search func(current string, path []string) []string
+	search = func(current string, path []string) []string {
+		rule := g.rules[current]
+		if rule == nil {
+			return nil
+		}
+		for _, ref := range leftRefs(rule.expr, nullable) {
+			if ref == name {
+				return append(path, ref)
+			}
+			if !visited[ref] {
+				visited[ref] = true
+				if found := search(ref, append(path, ref)); found != nil {
+					return found
+				}
+			}
+		}
+		return nil
+	}
+	return search(name, []string{name})
+}
+
+/*
+Returns the set of rules reachable from the root, including the root.
+*/
+func (g *Grammar) reachable(root string) map[string]bool {
+	reachable := map[string]bool{root: true}
+	pending := []string{root}
+	for len(pending) > 0 {
+		rule := g.rules[pending[0]]
+		pending = pending[1:]
+		if rule == nil {
+			continue
+		}
+		walk(rule.expr, func(expr Expr) {
+			if ref, ok := expr.(*Reference); ok && !reachable[ref.name] {
+				reachable[ref.name] = true
+				pending = append(pending, ref.name)
+			}
+		})
+	}
+	return reachable
+}
+
+/*
+Returns the literal text an expression must start with, if any, and whether
+it is matched regardless of case.
+*/
+func leadingLiteral(expr Expr) (string, bool) {
+	switch x := expr.(type) {
+	case *Literal:
+		return x.literal, false
+	case *FoldedLiteral:
+		return x.literal, true
+	case *Sequence:
+		if len(x.exprs) > 0 {
+			if lit, ok := x.exprs[0].(*FoldedLiteral); ok {
+				return lit.literal, true
+			}
+		}
+		var sb strings.Builder
+		for _, child := range x.exprs {
+			lit, ok := child.(*Literal)
+			if !ok {
+				break
+			}
+			sb.WriteString(lit.literal)
+		}
+		return sb.String(), false
+	}
+	return "", false
+}
+
+/*
+Returns true if earlier matches whenever later would, so later is never
+chosen. Only literals, folded literals, and classes shadow, and only
+alternatives that start with a literal, or the same class, are shadowed.
+Anything else, like a class covering every case of a folded literal, goes
+unreported.
+*/
+func shadows(earlier, later Expr) bool {
+	prefix, folded := leadingLiteral(later)
+	switch x := earlier.(type) {
+	case *Literal:
+		return !folded && strings.HasPrefix(prefix, x.literal)
+	case *FoldedLiteral:
+		return len(prefix) >= len(x.literal) && strings.EqualFold(prefix[:len(x.literal)], x.literal)
+	case *CharClass:
+		if cls, ok := later.(*CharClass); ok {
+			return cls.regex.String() == x.regex.String()
+		}
+		return !folded && prefix != "" && x.regex.MatchString(graphemesOf(prefix)[0])
+	}
+	return false
+}
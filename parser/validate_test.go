@@ -0,0 +1,198 @@
+package parser_test
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/fuwjax/gopase/parser"
+	"github.com/fuwjax/gopase/when"
+)
+
+func validate(t *testing.T, grammar string) parser.Problems {
+	t.Helper()
+	return when.YouErr(parser.Bootstrap(grammar)).ExpectSuccess(t).Validate()
+}
+
+func TestValidateClean(t *testing.T) {
+	when.You(validate(t, `
+S = A (',' A)* !.
+A = [a-z]+
+`)).Expect(t, parser.Problems(nil))
+	when.You(parser.PegGrammar().Validate()).Expect(t, parser.Problems(nil))
+}
+
+func TestValidateUndefined(t *testing.T) {
+	when.You(validate(t, `S = A B
+A = 'a'`)).Expect(t, parser.Problems{{parser.UndefinedReference, "S", "no such rule: B"}})
+}
+
+func TestValidateUnused(t *testing.T) {
+	when.You(validate(t, `S = 'a'
+A = B
+B = 'b'`)).Expect(t, parser.Problems{
+		{parser.UnusedRule, "A", "not reachable from S"},
+		{parser.UnusedRule, "B", "not reachable from S"},
+	})
+}
+
+func TestValidateDuplicate(t *testing.T) {
+	grammar := parser.NewGrammar().AddRule("S", parser.Lit("a")).AddRule("S", parser.Lit("b"))
+	when.You(grammar.Validate()).Expect(t, parser.Problems{{parser.DuplicateRule, "S", "defined more than once"}})
+}
+
+func TestValidateEmptyRepetition(t *testing.T) {
+	when.You(validate(t, `S = A* ('b'?)+
+A = 'a'?`)).Expect(t, parser.Problems{
+		{parser.EmptyRepetition, "S", "Rep(Ref(\"A\")) repeats an expression that can match empty"},
+		{parser.EmptyRepetition, "S", "Req(Opt(Lit(`b`))) repeats an expression that can match empty"},
+	})
+	when.You(validate(t, `S = $x('a'?) $x*`)).Expect(t, parser.Problems{
+		{parser.EmptyRepetition, "S", "Rep(BackRef(\"x\")) repeats an expression that can match empty"},
+	})
+}
+
+func TestValidateLeftRecursion(t *testing.T) {
+	when.You(validate(t, `S = A? T 'x' / 'y'
+T = S '+' / 'z'
+A = 'a'`)).Expect(t, parser.Problems{
+		{parser.LeftRecursion, "S", "S -> T -> S"},
+		{parser.LeftRecursion, "T", "T -> S -> T"},
+	})
+}
+
+func TestValidateShadowed(t *testing.T) {
+	when.You(validate(t, `S = 'a' / 'ab' / 'a' 'c' / 'b' / 'bc' [x]`)).Expect(t, parser.Problems{
+		{parser.ShadowedAlternative, "S", "Lit(`ab`) is shadowed by Lit(`a`)"},
+		{parser.ShadowedAlternative, "S", "Seq(Lit(`a`), Lit(`c`)) is shadowed by Lit(`a`)"},
+		{parser.ShadowedAlternative, "S", "Seq(Lit(`bc`), Cls(\"[x]\")) is shadowed by Lit(`b`)"},
+	})
+}
+
+func TestValidateShadowedFolded(t *testing.T) {
+	when.You(validate(t, `S = 'ab'i / 'ABC' / 'abd'i / 'x' / 'xy'i / [0-9] / '12' / [0-9] / 'q'i`)).Expect(t, parser.Problems{
+		{parser.ShadowedAlternative, "S", "Lit(`ABC`) is shadowed by LitI(`ab`)"},
+		{parser.ShadowedAlternative, "S", "LitI(`abd`) is shadowed by LitI(`ab`)"},
+		{parser.ShadowedAlternative, "S", "Lit(`12`) is shadowed by Cls(\"[0-9]\")"},
+		{parser.ShadowedAlternative, "S", "Cls(\"[0-9]\") is shadowed by Cls(\"[0-9]\")"},
+	})
+}
+
+func TestValidateBound(t *testing.T) {
+	grammar := parser.NewGrammar().AddRule("S", parser.Bound(parser.Lit("x"), 3, 2))
+	when.You(grammar.Validate()).Expect(t, parser.Problems{{parser.InvalidBound, "S", "Bound(Lit(`x`), 3, 2): min 3 is more than max 2"}})
+	when.YouErr(parser.Parse("S", grammar, nil, "xx")).ExpectError(t, "min 3 is more than max 2\nwhile in S")
+	when.YouErr(parser.NewProgram(grammar)).ExpectError(t, "rule S: min 3 is more than max 2")
+}
+
+func TestValidateNewParser(t *testing.T) {
+	parse := parser.NewParserFrom("S = A\nA = B", nil)
+	when.YouErr(parse("A", "")).ExpectError(t, "undefined reference in A: no such rule: B")
+
+	warned := parser.NewParser[string]("S", "S = 'a' / 'ab'", nil)
+	when.YouErr(warned("a")).Expect(t, "a")
+
+	when.YouErr(parser.CompileParserFrom("S = A\nA = B", nil)).ExpectError(t, "undefined reference in A: no such rule: B")
+	when.YouErr(parser.CompileParser[string]("S", "S = A\nA = B", nil)).ExpectError(t, "undefined reference in A: no such rule: B")
+	compiled := when.YouErr(parser.CompileParser[string]("S", "S = 'a' / 'ab'", nil)).ExpectSuccess(t)
+	when.YouErr(compiled("a")).Expect(t, "a")
+}
+
+func TestValidateParameterized(t *testing.T) {
+	when.You(validate(t, `S = List<A, ','> List<A>
+List<Item, Sep> = Item (Sep Item)*
+A = 'a'`)).Expect(t, parser.Problems{{parser.ArgumentMismatch, "S", "List takes 2 arguments, got 1"}})
+	when.You(validate(t, `S = List<Item>
+List<X> = X Item
+Item = 'a'`)).Expect(t, parser.Problems(nil))
+}
+
+type checkHandler struct{}
+
+func (checkHandler) S(results iter.Seq2[string, any]) (any, error) {
+	return "s", nil
+}
+
+func (checkHandler) A(results iter.Seq2[string, any], span parser.Span) (any, error) {
+	return "a", nil
+}
+
+func (checkHandler) Misspeled(results iter.Seq2[string, any]) (any, error) {
+	return nil, nil
+}
+
+func (checkHandler) B(results iter.Seq2[string, any]) string {
+	return "b"
+}
+
+func (checkHandler) String() string {
+	return "check"
+}
+
+func (checkHandler) helper(results iter.Seq2[string, any]) (any, error) {
+	return nil, nil
+}
+
+func TestCheckHandler(t *testing.T) {
+	grammar := when.YouErr(parser.Bootstrap("S = A B C\nA = 'a'\nB = 'b'\nC = 'c'")).ExpectSuccess(t)
+	when.You(grammar.CheckHandler(checkHandler{})).Expect(t, parser.Problems{
+		{parser.BadSignature, "B", "func(parser_test.checkHandler, iter.Seq2[string,interface {}]) string is not a Converter or SpanConverter"},
+		{parser.UnmatchedHandler, "Misspeled", "no rule named Misspeled"},
+		{parser.UnhandledRule, "B", "no converter, matches as text"},
+		{parser.UnhandledRule, "C", "no converter, matches as text"},
+	})
+}
+
+type probeHandler struct{}
+
+func (probeHandler) S(s string) (any, error) {
+	return s, nil
+}
+
+func (probeHandler) Name() string {
+	return "probe"
+}
+
+func TestCheckHandlerRuleNamed(t *testing.T) {
+	grammar := when.YouErr(parser.Bootstrap("S = 'a' json.Name\njson.Name = 'b'")).ExpectSuccess(t)
+	when.You(grammar.CheckHandler(probeHandler{})).Expect(t, parser.Problems{
+		{parser.BadSignature, "Name", "func(parser_test.probeHandler) string is not a Converter or SpanConverter"},
+		{parser.BadSignature, "S", "func(parser_test.probeHandler, string) (interface {}, error) is not a Converter or SpanConverter"},
+		{parser.UnhandledRule, "S", "no converter, matches as text"},
+		{parser.UnhandledRule, "json.Name", "no converter, matches as text"},
+	})
+	when.You(parser.ReflectSpanHandler(probeHandler{})("S") == nil).Expect(t, true)
+
+	problem := "bad signature in S: func(parser_test.probeHandler, string) (interface {}, error) is not a Converter or SpanConverter"
+	when.YouErr(parser.CompileParserFrom("S = 'a'", probeHandler{})).ExpectError(t, problem)
+	when.YouErr(parser.NewParserFrom("S = 'a'", probeHandler{})("S", "a")).ExpectError(t, problem)
+}
+
+func TestCheckHandlerMap(t *testing.T) {
+	grammar := when.YouErr(parser.Bootstrap("S = A\nA = 'a'\njson.String = 'b'")).ExpectSuccess(t)
+	converter := func(results iter.Seq2[string, any]) (any, error) { return nil, nil }
+	when.You(grammar.CheckHandler(map[string]parser.Converter{"S": converter, "Aa": converter, "String": converter})).Expect(t, parser.Problems{
+		{parser.UnmatchedHandler, "Aa", "no rule named Aa"},
+		{parser.UnmatchedHandler, "String", "no rule named String"},
+		{parser.UnhandledRule, "A", "no converter, matches as text"},
+		{parser.UnhandledRule, "json.String", "no converter, matches as text"},
+	})
+	when.You(grammar.CheckHandler(parser.TreeHandler)).Expect(t, parser.Problems(nil))
+}
+
+type Letter struct{}
+
+type Missing struct{}
+
+func TestCheckHandlerStruct(t *testing.T) {
+	grammar := when.YouErr(parser.Bootstrap("S = json.Letter\njson.Letter = 'a'")).ExpectSuccess(t)
+	when.You(grammar.CheckHandler(parser.StructHandler(Letter{}, &Missing{}))).Expect(t, parser.Problems{
+		{parser.UnmatchedHandler, "Missing", "no rule named Missing"},
+	})
+	parse := parser.NewParser[any]("S", "S = A\nA = 'a'", parser.StructHandler(Missing{}))
+	when.YouErr(parse("a")).ExpectError(t, "unmatched handler in Missing: no rule named Missing")
+}
+
+func TestCheckHandlerNewParser(t *testing.T) {
+	parse := parser.NewParser[string]("S", "S = A B C\nA = 'a'\nB = 'b'\nC = 'c'", checkHandler{})
+	when.YouErr(parse("abc")).ExpectError(t, "bad signature in B: func(parser_test.checkHandler, iter.Seq2[string,interface {}]) string is not a Converter or SpanConverter\nunmatched handler in Misspeled: no rule named Misspeled")
+}
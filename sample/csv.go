@@ -10,8 +10,8 @@ import (
 )
 
 const csvGrammar = `
-Records = EOL* Record (EOL+ Record)* EOL* EOF
-Record = !EOF Field (',' Field)* 
+Records = EOL* Record ~> EOL (EOL+ !EOF Record ~> EOL)* EOL* EOF
+Record = !EOF Field (',' Field)* &(EOL / EOF)
 Field = Quoted / Bare
 Quoted = WS '"' Inner '"' WS
 Inner = ([^"] / '""')*
@@ -21,10 +21,20 @@ EOL = [\n\r]
 EOF = !.
 `
 
+var CsvParserFrom = sync.OnceValue(func() parser.ParserFrom {
+	return parser.NewParserFrom(csvGrammar, csvHandler{})
+})
+var CsvProgramFrom = sync.OnceValue(func() parser.ParserFrom {
+	return parser.NewProgramParserFrom(csvGrammar, csvHandler{})
+})
 var CsvParser = sync.OnceValue(func() parser.Parser[[][]string] {
-	return parser.NewParser[[][]string]("Records", csvGrammar, csvHandler{})
+	return parser.NewRecoveringParser[[][]string]("Records", csvGrammar, csvHandler{})
 })
 
+/*
+Parses every well-formed record. Malformed records are skipped, and reported
+together in a parser.Diagnostics error.
+*/
 func ParseCsv(input string) ([][]string, error) {
 	return CsvParser()(input)
 }
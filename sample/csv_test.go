@@ -1,6 +1,8 @@
 package sample_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/fuwjax/gopase/sample"
@@ -31,3 +33,44 @@ a,b,c
 `)).Expect(t, []map[string]string{{"A": "a", "B": "b", "C": "c"}})
 	})
 }
+func TestCsvBadRecords(t *testing.T) {
+	t.Run("Csv Bad Records", func(t *testing.T) {
+		when.YouErr(sample.ParseCsv(`A,B,C
+"a"x,b,c
+d,e,f
+"g"g,h,i
+`)).ExpectError(t, "at 2:4 expected one of [ \\t], ','\nwhile in Record\nwhile in Records\n"+
+			"at 4:4 expected one of [ \\t], ','\nwhile in Record\nwhile in Records")
+		records, _ := sample.ParseCsv("A,B,C\n\"a\"x,b,c\nd,e,f\n")
+		when.You(records).Expect(t, [][]string{{"A", "B", "C"}, {"d", "e", "f"}})
+	})
+}
+
+func benchCsv() string {
+	var sb strings.Builder
+	sb.WriteString("id,name,note\n")
+	for i := range 500 {
+		fmt.Fprintf(&sb, "%d,item %d,\"quoted, \"\"note\"\" %d\"\n", i, i, i)
+	}
+	return sb.String()
+}
+
+func TestCsvProgram(t *testing.T) {
+	input := benchCsv()
+	expected := when.YouErr(sample.CsvParserFrom()("Records", input)).ExpectSuccess(t)
+	when.YouErr(sample.CsvProgramFrom()("Records", input)).Expect(t, expected)
+}
+
+func BenchmarkCsvParse(b *testing.B) {
+	input := benchCsv()
+	for b.Loop() {
+		sample.CsvParserFrom()("Records", input)
+	}
+}
+
+func BenchmarkCsvProgram(b *testing.B) {
+	input := benchCsv()
+	for b.Loop() {
+		sample.CsvProgramFrom()("Records", input)
+	}
+}
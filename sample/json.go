@@ -13,25 +13,33 @@ import (
 )
 
 const jsonGrammar = `
-Value = WS (String / Object / Array / Number / Literal) WS
-Object = "{" (WS String WS ":" Value ("," WS String WS ":" Value)* / WS) "}"
-Array = "[" (Value ("," Value)* / WS) "]"
-String = '"' ("\\u" Hex / "\\" Escape / Plain)* '"'
+%skip = [ \r\n\t]
+%lexical String Number
+Value = String / Object / Array / Number / Literal
+Object = "{" ^ ("}" / (String ":" Value) ~> [,}] ("," (String ":" Value) ~> [,}])* "}")
+Array = "[" ^ ("]" / Value ~> [,\]] ("," Value ~> [,\]])* "]")
+String = '"' ^ ("\\u" Hex / "\\" Escape / Plain)* '"'
 Number = "-"? ("0" / [1-9][0-9]*) ("." [0-9]+)? ([eE][+-]?[0-9]+)?
 Literal = "true" / "false" / "null"
 Plain = [^\\"]+
 Escape = [/\\"bfnrt]
-Hex = [0-9a-fA-F] [0-9a-fA-F] [0-9a-fA-F] [0-9a-fA-F]
-WS = [ \r\n\t]*
+Hex = [0-9a-fA-F]{4}
 `
 
 var JsonParserFrom = sync.OnceValue(func() parser.ParserFrom {
 	return parser.NewParserFrom(jsonGrammar, jsonHandler{})
 })
+var JsonProgramFrom = sync.OnceValue(func() parser.ParserFrom {
+	return parser.NewProgramParserFrom(jsonGrammar, jsonHandler{})
+})
 var JsonParser = sync.OnceValue(func() parser.Parser[any] {
-	return parser.NewParser[any]("Value", jsonGrammar, jsonHandler{})
+	return parser.NewRecoveringParser[any]("Value", jsonGrammar, jsonHandler{})
 })
 
+/*
+Parses a JSON value. Malformed array elements and object members are skipped,
+and reported together in a parser.Diagnostics error.
+*/
 func ParseJson(input string) (any, error) {
 	return JsonParser()(input)
 }
@@ -1,8 +1,12 @@
 package sample_test
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/fuwjax/gopase/parser"
 	"github.com/fuwjax/gopase/sample"
 	"github.com/fuwjax/gopase/when"
 )
@@ -16,6 +20,7 @@ func TestJson(t *testing.T) {
 	}
 	when.YouDoErr("Json String", parseJson(`"abcd"`)).Expect(t, "abcd")
 	when.YouDoErr("Json Escape", parseJson(`"\n"`)).Expect(t, "\n")
+	when.YouDoErr("Json Unicode Escape", parseJson(`"\u0041b"`)).Expect(t, "Ab")
 	when.YouDoErr("Json Number", parseJson(`3.4`)).Expect(t, 3.4)
 	when.YouDoErr("Json Array", parseJson(`[1,2,3.4]`)).Expect(t, []any{1.0, 2.0, 3.4})
 	when.YouDoErr("Json Object", parseJson(`{"A":"a","B":"b","C":"c"}`)).
@@ -25,4 +30,55 @@ func TestJson(t *testing.T) {
 			"B": "b",
 			"C": "c"
 		}`)).Expect(t, map[string]any{"A": "a", "B": "b", "C": "c"})
+	when.YouDoErr("Json Missing Comma", parseJson(`{"a":1 "b":2}`)).
+		ExpectError(t, "at 1:8 expected one of ',', '}'\nwhile in Object\nwhile in Value")
+	when.YouDoErr("Json Unterminated Array", parseJson(`[1,2`)).
+		ExpectError(t, "at 1:5 expected one of [0-9], '.', [eE], ',', ']'\nwhile in Array\nwhile in Value")
+}
+
+func TestJsonRecovery(t *testing.T) {
+	t.Run("Json Recovery", func(t *testing.T) {
+		value, err := sample.ParseJson(`{"a":[1,tru,3], "b" 2, "c":{}}`)
+		when.You(value).Expect(t, map[string]any{"a": []any{1.0, 3.0}, "c": map[string]any{}})
+		var diagnostics parser.Diagnostics
+		when.You(errors.As(err, &diagnostics)).ExpectSuccess(t)
+		when.You(len(diagnostics)).Expect(t, 2)
+		when.You(diagnostics[0].Offset).Expect(t, 8)
+		when.You(diagnostics[1].Offset).Expect(t, 20)
+	})
+}
+
+func benchJson() string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := range 200 {
+		if i > 0 {
+			sb.WriteString(",\n")
+		}
+		fmt.Fprintf(&sb, `{"id": %d, "name": "item é %d", "tags": ["a", "b\n"], "price": -%d.5e1, "ok": true, "none": null}`, i, i, i)
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+func TestJsonProgram(t *testing.T) {
+	input := benchJson()
+	expected := when.YouErr(sample.JsonParserFrom()("Value", input)).ExpectSuccess(t)
+	when.YouErr(sample.JsonProgramFrom()("Value", input)).Expect(t, expected)
+	_, expectedErr := sample.JsonParserFrom()("Value", `{"a": [1, }`)
+	when.YouErr(sample.JsonProgramFrom()("Value", `{"a": [1, }`)).ExpectError(t, expectedErr.Error())
+}
+
+func BenchmarkJsonParse(b *testing.B) {
+	input := benchJson()
+	for b.Loop() {
+		sample.JsonParserFrom()("Value", input)
+	}
+}
+
+func BenchmarkJsonProgram(b *testing.B) {
+	input := benchJson()
+	for b.Loop() {
+		sample.JsonProgramFrom()("Value", input)
+	}
 }
@@ -15,10 +15,18 @@ const template = `
 ( ^=Optional^)(^>parser^)Opt((^*expr^)(^>type[.]^)(^/^))(^/^)
 ( ^=Repeated^)(^>parser^)Rep((^*expr^)(^>type[.]^)(^/^))(^/^)
 ( ^=Required^)(^>parser^)Req((^*expr^)(^>type[.]^)(^/^))(^/^)
+( ^=Bounded^)(^>parser^)Bound((^*expr^)(^>type[.]^)(^/^), (^min^), (^max^))(^/^)
+( ^=Recovery^)(^>parser^)Recover((^*expr^)(^>type[.]^)(^/^), (^*sync^)(^>type[.]^)(^/^))(^/^)
 ( ^=CharClass^)(^>parser^)Cls(` + "`(^regex^)`" + `)(^/^)
 ( ^=Literal^)(^>parser^)Lit(` + "`(^literal^)`" + `)(^/^)
+( ^=FoldedLiteral^)(^>parser^)LitI(` + "`(^literal^)`" + `)(^/^)
 ( ^=Any^)(^>parser^)Dot()(^/^)
-( ^=Reference^)(^>parser^)Ref("(^name^)")(^/^)
+( ^=Commit^)(^>parser^)Cut()(^/^)
+( ^=Reference^)(^>parser^)Ref("(^name^)"(^*args^), (^>type[.]^)(^/^))(^/^)
+( ^=Labeled^)(^>parser^)Label("(^label^)", (^*expr^)(^>type[.]^)(^/^))(^/^)
+( ^=Capturing^)(^>parser^)Capture((^*expr^)(^>type[.]^)(^/^))(^/^)
+( ^=Binding^)(^>parser^)Bind("(^name^)", (^*expr^)(^>type[.]^)(^/^))(^/^)
+( ^=BackReference^)(^>parser^)BackRef("(^name^)")(^/^)
 ( ^=PositiveLookahead^)(^>parser^)See((^*expr^)(^>type[.]^)(^/^))(^/^)
 ( ^=NegativeLookahead^)(^>parser^)Not((^*expr^)(^>type[.]^)(^/^))(^/^ )
 
@@ -32,7 +40,19 @@ import "github.com/fuwjax/gopase/parser"
 func (^name^)Grammar() *(^>parser^)Grammar {
 	grammar := (^>parser^)NewGrammar()
 	(^*grammar.Rules^ )
-	grammar.AddRule("(^@^)", (^*expr^)(^>type[.]^)(^/^))
+	(^*description^)grammar.Add((^>parser^)NewRule("(^name^)", (^*expr^)(^>type[.]^)(^/^)(^*params^), "(^.^)"(^/^)).Describe(` + "`(^description^)`" + `))(^/^)(^!description^)grammar.AddRule("(^@^)", (^*expr^)(^>type[.]^)(^/^)(^*params^), "(^.^)"(^/^))(^/^)
+	(^/^ )
+	(^*grammar.Tokens^ )
+	grammar.Token("(^.^)")
+	(^/^ )
+	(^*grammar.Ignored^ )
+	grammar.Ignore("(^.^)")
+	(^/^ )
+	(^*grammar.Lexicals^ )
+	grammar.Lexical("(^.^)")
+	(^/^ )
+	(^*grammar.SkipExpr^ )
+	grammar.Skip((^>type[.]^))
 	(^/^ )
 	return grammar
 }
@@ -2,6 +2,7 @@ package sample_test
 
 import (
 	"os"
+	"path/filepath"
 	"slices"
 	"testing"
 
@@ -35,3 +36,30 @@ func TestPegTemplate(t *testing.T) {
 			ExpectMatch(t, MatchGraphemes(string(contents)))
 	})
 }
+
+func TestPegTemplateFeatures(t *testing.T) {
+	tests := []struct {
+		name    string
+		grammar string
+	}{
+		{"Number", `Number "a number" = [0-9]+`},
+		{"List", "S = List<'a', ','>\nList<Item, Sep> = Item (Sep Item)*"},
+		{"Range", `Range = lo:N '..' hi:N`},
+		{"Word", `Word = $([a-z]+)`},
+		{"Select", `Select = "select"i [a-z]i`},
+		{"Octet", `Octet = [0-9]{1,3} [.]{0,} [a-f]{4}`},
+		{"Block", `Block = '{' ^ [a-z]* '}'`},
+		{"Fence", "Fence = $fence([~]+) (!$fence .)* $fence"},
+		{"Tokens", "%token Num\n%ignore WS\nSum = Num ('+' Num)*\nNum = [0-9]+\nWS = ' '+"},
+		{"Skip", "%skip = [ \\t]\n%lexical Num\nSum = Num ('+' Num)*\nNum = [0-9]+"},
+	}
+	for _, tt := range tests {
+		t.Run("PegTemplate "+tt.name, func(t *testing.T) {
+			params := map[string]any{"package": "sample", "name": tt.name, "inPackage": false}
+			grammar := when.YouErr(parser.Bootstrap(tt.grammar)).ExpectSuccess(t)
+			contents := when.YouErr(os.ReadFile(filepath.Join("testdata", tt.name+".gold"))).ExpectSuccess(t)
+
+			when.YouErr(sample.RenderPeg(grammar, params)).ExpectMatch(t, MatchGraphemes(string(contents)))
+		})
+	}
+}